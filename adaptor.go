@@ -1,6 +1,8 @@
 package lgr
 
 import (
+	"fmt"
+	"io"
 	"log"
 	"strings"
 )
@@ -8,21 +10,47 @@ import (
 // Writer holds lgr.L and wraps with io.Writer interface
 type Writer struct {
 	L
-	level string // if defined added to each message
+	level   string                   // if defined added to each message
+	levelFn func(line string) string // if defined, called for each message to detect level
 }
 
 // Write to lgr.L
 func (w *Writer) Write(p []byte) (n int, err error) {
+	if w.levelFn != nil {
+		line := string(p)
+		w.Logf(withLevel(w.levelFn(line)) + line)
+		return len(p), nil
+	}
 	w.Logf(w.level + string(p))
 	return len(p), nil
 }
 
 // ToWriter makes io.Writer for given lgr.L with optional level
 func ToWriter(l L, level string) *Writer {
+	return &Writer{L: l, level: withLevel(level)}
+}
+
+// ToWriterFunc makes io.Writer for given lgr.L with level detected for each line by levelFn,
+// i.e. by inspecting JSON output of a child process. Empty level keeps the line as is.
+func ToWriterFunc(l L, levelFn func(line string) string) *Writer {
+	return &Writer{L: l, levelFn: levelFn}
+}
+
+// withLevel adds space to non-empty level
+func withLevel(level string) string {
 	if level != "" && !strings.HasSuffix(level, " ") {
 		level += " "
 	}
-	return &Writer{l, level}
+	return level
+}
+
+// Writer returns io.Writer adding given level to each message, i.e. l.Writer("DEBUG"). Writers cached per level.
+func (l *Logger) Writer(level string) io.Writer {
+	if w, ok := l.writers.Load(level); ok {
+		return w.(*Writer)
+	}
+	w, _ := l.writers.LoadOrStore(level, ToWriter(l, level))
+	return w.(*Writer)
 }
 
 // ToStdLogger makes standard logger
@@ -30,6 +58,15 @@ func ToStdLogger(l L, level string) *log.Logger {
 	return log.New(ToWriter(l, level), "", 0)
 }
 
+// ToStdLoggerWithCaller makes standard logger on top of *Logger with caller depth compensated for std logger frames,
+// so caller info reflects the real caller of log.Logger methods rather than the adaptor
+func ToStdLoggerWithCaller(l *Logger, level string) *log.Logger {
+	res := l.clone()
+	res.callerDepth += 3     // skip 3 more frames to compensate log.Logger calls
+	res.reTrace = reTraceStd // stack trace split on log/ path
+	return ToStdLogger(res, level)
+}
+
 // SetupStdLogger makes the default std logger with lgr.L
 func SetupStdLogger(opts ...Option) {
 	logOpts := append([]Option{CallerDepth(3)}, opts...) // skip 3 more frames to compensate stdlog calls
@@ -39,3 +76,60 @@ func SetupStdLogger(opts ...Option) {
 	log.SetPrefix("")
 	log.SetFlags(0)
 }
+
+// StdLog provides log.Logger-like methods on top of *Logger, see Logger.Std
+type StdLog struct {
+	l *Logger
+}
+
+// Std returns log.Logger-like method set for *Logger, so code using *log.Logger methods can switch to lgr
+// with minimal changes. Levels can be passed as message prefixes, the same way as for Logf.
+func (l *Logger) Std() StdLog { return StdLog{l: l} }
+
+// Print logs message formatted with fmt.Sprint
+func (s StdLog) Print(v ...interface{}) { s.l.logf(fmt.Sprint(v...)) }
+
+// Printf logs message formatted with fmt.Sprintf
+func (s StdLog) Printf(format string, v ...interface{}) { s.l.logf(format, v...) }
+
+// Println logs message formatted with fmt.Sprintln
+func (s StdLog) Println(v ...interface{}) { s.l.logf(fmt.Sprintln(v...)) }
+
+// Fatal is equivalent to Print followed by exit
+func (s StdLog) Fatal(v ...interface{}) {
+	s.l.logf(fmt.Sprint(v...))
+	s.l.exit()
+}
+
+// Fatalf is equivalent to Printf followed by exit
+func (s StdLog) Fatalf(format string, v ...interface{}) {
+	s.l.logf(format, v...)
+	s.l.exit()
+}
+
+// Fatalln is equivalent to Println followed by exit
+func (s StdLog) Fatalln(v ...interface{}) {
+	s.l.logf(fmt.Sprintln(v...))
+	s.l.exit()
+}
+
+// Panic is equivalent to Print followed by panic
+func (s StdLog) Panic(v ...interface{}) {
+	msg := fmt.Sprint(v...)
+	s.l.logf(msg)
+	panic(msg)
+}
+
+// Panicf is equivalent to Printf followed by panic
+func (s StdLog) Panicf(format string, v ...interface{}) {
+	msg := fmt.Sprintf(format, v...)
+	s.l.logf(msg)
+	panic(msg)
+}
+
+// Panicln is equivalent to Println followed by panic
+func (s StdLog) Panicln(v ...interface{}) {
+	msg := fmt.Sprintln(v...)
+	s.l.logf(msg)
+	panic(msg)
+}
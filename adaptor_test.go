@@ -3,6 +3,7 @@ package lgr
 import (
 	"bytes"
 	"log"
+	"strings"
 	"testing"
 	"time"
 
@@ -69,3 +70,96 @@ func TestSetupStdLogger(t *testing.T) {
 	log.Print("[DEBUG] something\n")
 	assert.Empty(t, rout.String())
 }
+
+func TestLogger_Writer(t *testing.T) {
+	rout, rerr := bytes.NewBuffer([]byte{}), bytes.NewBuffer([]byte{})
+	l := New(Out(rout), Err(rerr), Debug, Format(WithMsec))
+	l.now = func() time.Time { return time.Date(2018, 1, 7, 13, 2, 34, 0, time.Local) }
+
+	_, err := l.Writer("DEBUG").Write([]byte("something blah 123"))
+	require.NoError(t, err)
+	_, err = l.Writer("ERROR").Write([]byte("something bad"))
+	require.NoError(t, err)
+	assert.Equal(t, "2018/01/07 13:02:34.000 DEBUG something blah 123\n2018/01/07 13:02:34.000 ERROR something bad\n", rout.String())
+	assert.Equal(t, "2018/01/07 13:02:34.000 ERROR something bad\n", rerr.String())
+
+	assert.True(t, l.Writer("DEBUG") == l.Writer("DEBUG"), "cached")
+	assert.False(t, l.Writer("DEBUG") == l.Writer("INFO"))
+
+	rout.Reset()
+	l = New(Out(rout), Err(rerr), Debug, Format(`{{.Level}} {{.Tags}} {{.Message}}`))
+	_, err = l.Tagged("db").Writer("DEBUG").Write([]byte("query"))
+	require.NoError(t, err)
+	assert.Equal(t, "DEBUG #db query\n", rout.String(), "child writer bound to child")
+	assert.False(t, l.Writer("DEBUG") == l.Tagged("db").Writer("DEBUG"))
+}
+
+func TestAdaptor_ToWriterFunc(t *testing.T) {
+	rout, rerr := bytes.NewBuffer([]byte{}), bytes.NewBuffer([]byte{})
+	l := New(Out(rout), Err(rerr), Format(`{{.Level}} {{.Message}}`))
+
+	wr := ToWriterFunc(l, func(line string) string {
+		switch {
+		case strings.Contains(line, `"level":"error"`):
+			return "ERROR"
+		case strings.Contains(line, `"level":"warning"`):
+			return "WARN "
+		}
+		return ""
+	})
+	for _, line := range []string{`{"level":"error","msg":"failed"}`, `{"level":"warning","msg":"careful"}`, `{"msg":"something"}`} {
+		sz, err := wr.Write([]byte(line + "\n"))
+		require.NoError(t, err)
+		assert.Equal(t, len(line)+1, sz)
+	}
+	assert.Equal(t, "ERROR {\"level\":\"error\",\"msg\":\"failed\"}\nWARN  {\"level\":\"warning\",\"msg\":\"careful\"}\n"+
+		"INFO  {\"msg\":\"something\"}\n", rout.String())
+	assert.Equal(t, "ERROR {\"level\":\"error\",\"msg\":\"failed\"}\n", rerr.String())
+}
+
+func TestAdaptor_ToStdLoggerWithCaller(t *testing.T) {
+	rout, rerr := bytes.NewBuffer([]byte{}), bytes.NewBuffer([]byte{})
+	l := New(Out(rout), Err(rerr), Format(`{{.Level}} ({{.CallerFile}}:{{.CallerLine}} {{.CallerFunc}}) {{.Message}}`))
+
+	wr := ToStdLoggerWithCaller(l, "WARN")
+	wr.Print("something")
+	wr.Printf("xxx %s", "yyy")
+	wr.Println("zzz")
+	assert.Equal(t, "WARN  (lgr/adaptor_test.go:125 lgr.TestAdaptor_ToStdLoggerWithCaller) something\n"+
+		"WARN  (lgr/adaptor_test.go:126 lgr.TestAdaptor_ToStdLoggerWithCaller) xxx yyy\n"+
+		"WARN  (lgr/adaptor_test.go:127 lgr.TestAdaptor_ToStdLoggerWithCaller) zzz\n", rout.String())
+
+	rout.Reset()
+	l.Logf("INFO parent not affected")
+	assert.Equal(t, "INFO  (lgr/adaptor_test.go:133 lgr.TestAdaptor_ToStdLoggerWithCaller) parent not affected\n", rout.String())
+}
+
+func TestLogger_Std(t *testing.T) {
+	fatalCalls := 0
+	rout, rerr := bytes.NewBuffer([]byte{}), bytes.NewBuffer([]byte{})
+	l := New(Out(rout), Err(rerr), CallerFunc, Format(`{{.Level}} {{.CallerFunc}} {{.Message}}`))
+	l.fatal = func() { fatalCalls++ }
+
+	std := l.Std()
+	std.Print("something ", 1)
+	std.Printf("WARN something %d", 2)
+	std.Println("something", 3)
+	std.Print("100%")
+	assert.Equal(t, "INFO  lgr.TestLogger_Std something 1\nWARN  lgr.TestLogger_Std something 2\n"+
+		"INFO  lgr.TestLogger_Std something 3\nINFO  lgr.TestLogger_Std 100%\n", rout.String())
+
+	rout.Reset()
+	std.Fatal("fatal ", 1)
+	std.Fatalf("fatal %d", 2)
+	std.Fatalln("fatal", 3)
+	assert.Equal(t, 3, fatalCalls)
+	assert.Equal(t, "INFO  lgr.TestLogger_Std fatal 1\nINFO  lgr.TestLogger_Std fatal 2\nINFO  lgr.TestLogger_Std fatal 3\n",
+		rout.String())
+
+	rout.Reset()
+	assert.PanicsWithValue(t, "ERROR panic 1", func() { std.Panic("ERROR panic ", 1) })
+	assert.PanicsWithValue(t, "panic 2", func() { std.Panicf("panic %d", 2) })
+	assert.PanicsWithValue(t, "panic 3\n", func() { std.Panicln("panic", 3) })
+	assert.Equal(t, "ERROR lgr.TestLogger_Std.func2 panic 1\nINFO  lgr.TestLogger_Std.func3 panic 2\n"+
+		"INFO  lgr.TestLogger_Std.func4 panic 3\n", rout.String())
+}
@@ -0,0 +1,101 @@
+package benchmarks
+
+import (
+	"log"
+	"testing"
+
+	"github.com/go-pkgz/lgr"
+)
+
+func BenchmarkStdLog(b *testing.B) {
+	l := log.New(nopWriter{}, "", log.LstdFlags)
+	b.ReportAllocs()
+	for n := 0; n < b.N; n++ {
+		l.Printf("INFO some message %d, %s", n, "string arg")
+	}
+}
+
+func BenchmarkStdLogCaller(b *testing.B) {
+	l := log.New(nopWriter{}, "", log.LstdFlags|log.Lshortfile)
+	b.ReportAllocs()
+	for n := 0; n < b.N; n++ {
+		l.Printf("INFO some message %d, %s", n, "string arg")
+	}
+}
+
+func BenchmarkStdLogFields(b *testing.B) {
+	l := log.New(nopWriter{}, "", log.LstdFlags)
+	b.ReportAllocs()
+	for n := 0; n < b.N; n++ {
+		l.Printf("INFO some message user=%s id=%d ok=%v", "joe", n, true)
+	}
+}
+
+func BenchmarkLgr(b *testing.B) {
+	l := lgr.New(lgr.Out(nopWriter{}), lgr.Err(nopWriter{}))
+	b.ReportAllocs()
+	for n := 0; n < b.N; n++ {
+		l.Logf("INFO some message %d, %s", n, "string arg")
+	}
+}
+
+func BenchmarkLgrCaller(b *testing.B) {
+	l := lgr.New(lgr.Out(nopWriter{}), lgr.Err(nopWriter{}), lgr.CallerFile)
+	b.ReportAllocs()
+	for n := 0; n < b.N; n++ {
+		l.Logf("INFO some message %d, %s", n, "string arg")
+	}
+}
+
+func BenchmarkLgrFields(b *testing.B) {
+	l := lgr.New(lgr.Out(nopWriter{}), lgr.Err(nopWriter{}))
+	b.ReportAllocs()
+	for n := 0; n < b.N; n++ {
+		l.Logf("INFO some message user=%s id=%d ok=%v", "joe", n, true)
+	}
+}
+
+func BenchmarkLgrWith(b *testing.B) {
+	l := lgr.New(lgr.Out(nopWriter{}), lgr.Err(nopWriter{})).With("user", "joe").With("ok", true)
+	b.ReportAllocs()
+	for n := 0; n < b.N; n++ {
+		l.Logf("INFO some message %d", n)
+	}
+}
+
+func BenchmarkLgrLogw(b *testing.B) {
+	l := lgr.New(lgr.Out(nopWriter{}), lgr.Err(nopWriter{}))
+	b.ReportAllocs()
+	for n := 0; n < b.N; n++ {
+		l.Infow("some message", "user", "joe", "id", n, "ok", true)
+	}
+}
+
+func BenchmarkLgrLogfmtLogw(b *testing.B) {
+	l := lgr.New(lgr.Out(nopWriter{}), lgr.Err(nopWriter{}), lgr.Format(lgr.Logfmt))
+	b.ReportAllocs()
+	for n := 0; n < b.N; n++ {
+		l.Infow("some message", "user", "joe", "id", n, "ok", true)
+	}
+}
+
+func BenchmarkLgrTemplate(b *testing.B) {
+	l := lgr.New(lgr.Out(nopWriter{}), lgr.Err(nopWriter{}), lgr.Format(lgr.FullDebug))
+	b.ReportAllocs()
+	for n := 0; n < b.N; n++ {
+		l.Logf("INFO some message %d, %s", n, "string arg")
+	}
+}
+
+func BenchmarkLgrFiltered(b *testing.B) {
+	l := lgr.New(lgr.Out(nopWriter{}), lgr.Err(nopWriter{}))
+	b.ReportAllocs()
+	for n := 0; n < b.N; n++ {
+		l.Logf("DEBUG some message %d, %s", n, "string arg")
+	}
+}
+
+// nopWriter discards writes, io.Discard not used as std log skips formatting for it
+type nopWriter struct{}
+
+func (nopWriter) Write(p []byte) (int, error) { return len(p), nil }
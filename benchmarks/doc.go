@@ -0,0 +1,5 @@
+// Package benchmarks compares lgr with the standard log, log/slog, zap and zerolog for common scenarios: plain
+// messages, messages with caller info, messages with fields, child loggers with fields (With) and per-call
+// key-value pairs (Logw). It is a separate module, so the comparison deps don't leak into lgr's go.mod.
+// Run with cd benchmarks && go test -bench=. -benchmem
+package benchmarks
@@ -0,0 +1,50 @@
+//go:build go1.21
+
+package benchmarks
+
+import (
+	"log/slog"
+	"testing"
+
+	"github.com/go-pkgz/lgr"
+)
+
+func BenchmarkSlogText(b *testing.B) {
+	l := slog.New(slog.NewTextHandler(nopWriter{}, nil))
+	b.ReportAllocs()
+	for n := 0; n < b.N; n++ {
+		l.Info("some message", "n", n, "arg", "string arg")
+	}
+}
+
+func BenchmarkSlogTextCaller(b *testing.B) {
+	l := slog.New(slog.NewTextHandler(nopWriter{}, &slog.HandlerOptions{AddSource: true}))
+	b.ReportAllocs()
+	for n := 0; n < b.N; n++ {
+		l.Info("some message", "n", n, "arg", "string arg")
+	}
+}
+
+func BenchmarkSlogTextFields(b *testing.B) {
+	l := slog.New(slog.NewTextHandler(nopWriter{}, nil))
+	b.ReportAllocs()
+	for n := 0; n < b.N; n++ {
+		l.Info("some message", "user", "joe", "id", n, "ok", true)
+	}
+}
+
+func BenchmarkSlogTextWith(b *testing.B) {
+	l := slog.New(slog.NewTextHandler(nopWriter{}, nil)).With("user", "joe", "ok", true)
+	b.ReportAllocs()
+	for n := 0; n < b.N; n++ {
+		l.Info("some message", "n", n)
+	}
+}
+
+func BenchmarkSlogLgrHandlerFields(b *testing.B) {
+	l := slog.New(lgr.SlogHandler(lgr.New(lgr.Out(nopWriter{}), lgr.Err(nopWriter{}))))
+	b.ReportAllocs()
+	for n := 0; n < b.N; n++ {
+		l.Info("some message", "user", "joe", "id", n, "ok", true)
+	}
+}
@@ -0,0 +1,61 @@
+package benchmarks
+
+import (
+	"testing"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+func newZap(opts ...zap.Option) *zap.Logger {
+	enc := zapcore.NewJSONEncoder(zap.NewProductionEncoderConfig())
+	return zap.New(zapcore.NewCore(enc, zapcore.AddSync(nopWriter{}), zap.InfoLevel), opts...)
+}
+
+func BenchmarkZap(b *testing.B) {
+	l := newZap().Sugar()
+	b.ReportAllocs()
+	for n := 0; n < b.N; n++ {
+		l.Infof("some message %d, %s", n, "string arg")
+	}
+}
+
+func BenchmarkZapCaller(b *testing.B) {
+	l := newZap(zap.AddCaller()).Sugar()
+	b.ReportAllocs()
+	for n := 0; n < b.N; n++ {
+		l.Infof("some message %d, %s", n, "string arg")
+	}
+}
+
+func BenchmarkZapFields(b *testing.B) {
+	l := newZap()
+	b.ReportAllocs()
+	for n := 0; n < b.N; n++ {
+		l.Info("some message", zap.String("user", "joe"), zap.Int("id", n), zap.Bool("ok", true))
+	}
+}
+
+func BenchmarkZapWith(b *testing.B) {
+	l := newZap().With(zap.String("user", "joe"), zap.Bool("ok", true)).Sugar()
+	b.ReportAllocs()
+	for n := 0; n < b.N; n++ {
+		l.Infof("some message %d", n)
+	}
+}
+
+func BenchmarkZapSugarLogw(b *testing.B) {
+	l := newZap().Sugar()
+	b.ReportAllocs()
+	for n := 0; n < b.N; n++ {
+		l.Infow("some message", "user", "joe", "id", n, "ok", true)
+	}
+}
+
+func BenchmarkZapFiltered(b *testing.B) {
+	l := newZap().Sugar()
+	b.ReportAllocs()
+	for n := 0; n < b.N; n++ {
+		l.Debugf("some message %d, %s", n, "string arg")
+	}
+}
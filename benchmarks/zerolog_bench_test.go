@@ -0,0 +1,55 @@
+package benchmarks
+
+import (
+	"testing"
+
+	"github.com/rs/zerolog"
+)
+
+func BenchmarkZerolog(b *testing.B) {
+	l := zerolog.New(nopWriter{}).With().Timestamp().Logger()
+	b.ReportAllocs()
+	for n := 0; n < b.N; n++ {
+		l.Info().Msgf("some message %d, %s", n, "string arg")
+	}
+}
+
+func BenchmarkZerologCaller(b *testing.B) {
+	l := zerolog.New(nopWriter{}).With().Timestamp().Caller().Logger()
+	b.ReportAllocs()
+	for n := 0; n < b.N; n++ {
+		l.Info().Msgf("some message %d, %s", n, "string arg")
+	}
+}
+
+func BenchmarkZerologFields(b *testing.B) {
+	l := zerolog.New(nopWriter{}).With().Timestamp().Logger()
+	b.ReportAllocs()
+	for n := 0; n < b.N; n++ {
+		l.Info().Str("user", "joe").Int("id", n).Bool("ok", true).Msg("some message")
+	}
+}
+
+func BenchmarkZerologWith(b *testing.B) {
+	l := zerolog.New(nopWriter{}).With().Timestamp().Str("user", "joe").Bool("ok", true).Logger()
+	b.ReportAllocs()
+	for n := 0; n < b.N; n++ {
+		l.Info().Msgf("some message %d", n)
+	}
+}
+
+func BenchmarkZerologLogw(b *testing.B) {
+	l := zerolog.New(nopWriter{}).With().Timestamp().Logger()
+	b.ReportAllocs()
+	for n := 0; n < b.N; n++ {
+		l.Info().Fields([]interface{}{"user", "joe", "id", n, "ok", true}).Msg("some message")
+	}
+}
+
+func BenchmarkZerologFiltered(b *testing.B) {
+	l := zerolog.New(nopWriter{}).Level(zerolog.InfoLevel).With().Timestamp().Logger()
+	b.ReportAllocs()
+	for n := 0; n < b.N; n++ {
+		l.Debug().Msgf("some message %d, %s", n, "string arg")
+	}
+}
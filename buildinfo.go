@@ -0,0 +1,66 @@
+package lgr
+
+import (
+	"os"
+	"runtime"
+	"runtime/debug"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// BuildInfoFields returns build info of the binary: "version" of the main module, "revision", "time" and "modified"
+// from VCS stamping (if available) and "go" version
+func BuildInfoFields() map[string]string {
+	res := map[string]string{"go": runtime.Version()}
+	bi, ok := debug.ReadBuildInfo()
+	if !ok {
+		return res
+	}
+	if bi.Main.Version != "" {
+		res["version"] = bi.Main.Version
+	}
+	for _, s := range bi.Settings {
+		switch s.Key {
+		case "vcs.revision":
+			res["revision"] = s.Value
+		case "vcs.time":
+			res["time"] = s.Value
+		case "vcs.modified":
+			res["modified"] = s.Value
+		}
+	}
+	return res
+}
+
+// formatFields makes sorted key=value string from fields
+func formatFields(fields map[string]string) string {
+	keys := make([]string, 0, len(fields))
+	for k := range fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	parts := make([]string, 0, len(keys))
+	for _, k := range keys {
+		parts = append(parts, k+"="+fields[k])
+	}
+	return strings.Join(parts, " ")
+}
+
+// LogStartup logs INFO record with process environment: host, pid, GOMAXPROCS, build info and logger settings.
+// Standardizes the first record every service logs.
+func (l *Logger) LogStartup() {
+	fields := BuildInfoFields()
+	fields["host"], _ = os.Hostname()
+	fields["pid"] = strconv.Itoa(os.Getpid())
+	fields["gomaxprocs"] = strconv.Itoa(runtime.GOMAXPROCS(0))
+	fields["cpus"] = strconv.Itoa(runtime.NumCPU())
+	fields["log.debug"] = strconv.FormatBool(l.enabled("DEBUG"))
+	fields["log.trace"] = strconv.FormatBool(l.enabled("TRACE"))
+	fields["log.caller"] = strconv.FormatBool(l.callerOn)
+	fields["log.format"] = "options"
+	if l.format != "" {
+		fields["log.format"] = "template"
+	}
+	l.logf("INFO startup, %s", formatFields(fields))
+}
@@ -0,0 +1,43 @@
+package lgr
+
+import (
+	"bytes"
+	"os"
+	"runtime"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuildInfoFields(t *testing.T) {
+	fields := BuildInfoFields()
+	assert.Equal(t, runtime.Version(), fields["go"])
+	_, ok := fields["version"]
+	assert.True(t, ok, "test binary has main module version")
+}
+
+func TestLoggerBuildBanner(t *testing.T) {
+	rout := bytes.NewBuffer([]byte{})
+	New(Out(rout), BuildBanner, CallerFunc, Format(`{{.Level}} {{.CallerFunc}} {{.Message}}`))
+	assert.Contains(t, rout.String(), "INFO  lgr.TestLoggerBuildBanner build info, ")
+	assert.Contains(t, rout.String(), " go="+runtime.Version())
+}
+
+func TestFormatFields(t *testing.T) {
+	assert.Equal(t, "a=1 b=2 c=3", formatFields(map[string]string{"c": "3", "a": "1", "b": "2"}))
+	assert.Equal(t, "", formatFields(nil))
+}
+
+func TestLogger_LogStartup(t *testing.T) {
+	rout := bytes.NewBuffer([]byte{})
+	l := New(Out(rout), Debug, CallerFunc, Format(`{{.Level}} {{.CallerFunc}} {{.Message}}`))
+	l.LogStartup()
+	out := rout.String()
+	assert.Contains(t, out, "INFO  lgr.TestLogger_LogStartup startup, ")
+	assert.Contains(t, out, " go="+runtime.Version())
+	assert.Contains(t, out, " log.caller=true log.debug=true log.format=template log.trace=false ")
+	assert.Contains(t, out, " pid="+strconv.Itoa(os.Getpid()))
+	assert.Contains(t, out, " gomaxprocs=")
+	assert.Contains(t, out, " host=")
+}
@@ -0,0 +1,43 @@
+package lgr
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// clockSkewMin is the smallest reported jump, smaller changes attributed to clock slewing by NTP
+const clockSkewMin = 250 * time.Millisecond
+
+// clockMonitor detects wall clock jumping backwards between records. Shared with child loggers.
+// For times with monotonic clock reading, the offset of wall clock from monotonic clock compared, so records
+// timestamped concurrently and checked out of order don't look like a jump.
+type clockMonitor struct {
+	ref    time.Time // reference time with monotonic clock reading
+	offset int64     // atomic, wall clock time minus monotonic clock time elapsed since ref, of the last record
+	last   int64     // atomic, unix nanoseconds of the last record without monotonic clock reading
+}
+
+func newClockMonitor() *clockMonitor {
+	return &clockMonitor{ref: time.Now()}
+}
+
+// check stores time of the record and returns how far the clock moved backwards since the previous record, 0 if not
+func (c *clockMonitor) check(dt time.Time) time.Duration {
+	var skew time.Duration
+	if hasMonotonic(dt) && hasMonotonic(c.ref) {
+		offset := dt.Round(0).Sub(c.ref.Round(0)) - dt.Sub(c.ref) // Round(0) strips monotonic reading
+		skew = time.Duration(atomic.SwapInt64(&c.offset, int64(offset))) - offset
+	} else {
+		ts := dt.UnixNano()
+		skew = time.Duration(atomic.SwapInt64(&c.last, ts) - ts)
+	}
+	if skew < clockSkewMin {
+		return 0
+	}
+	return skew
+}
+
+// hasMonotonic checks if time has monotonic clock reading, i.e. made by time.Now
+func hasMonotonic(t time.Time) bool {
+	return t != t.Round(0)
+}
@@ -0,0 +1,66 @@
+package lgr
+
+import (
+	"bytes"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClockMonitor(t *testing.T) {
+	c := clockMonitor{}
+	ts := time.Date(2018, 1, 7, 13, 2, 34, 0, time.UTC)
+	assert.Equal(t, time.Duration(0), c.check(ts))
+	assert.Equal(t, time.Duration(0), c.check(ts), "same time is not a skew")
+	assert.Equal(t, time.Duration(0), c.check(ts.Add(time.Second)))
+	assert.Equal(t, 3*time.Second, c.check(ts.Add(-2*time.Second)))
+	assert.Equal(t, time.Duration(0), c.check(ts.Add(-time.Second)), "no skew after the jump")
+}
+
+func TestLoggerClockSkewWarn(t *testing.T) {
+	rout := bytes.NewBuffer([]byte{})
+	l := New(Out(rout), ClockSkewWarn, CallerFunc, Format(`{{.DT.Format "15:04:05"}} {{.Level}} {{.CallerFunc}} {{.Message}}`))
+	ts := time.Date(2018, 1, 7, 13, 2, 34, 0, time.UTC)
+	l.now = func() time.Time { return ts }
+
+	l.Logf("INFO first")
+	ts = ts.Add(-time.Minute)
+	l.Logf("INFO second")
+	l.Logf("INFO third")
+	assert.Equal(t, "13:02:34 INFO  lgr.TestLoggerClockSkewWarn first\n"+
+		"13:01:34 WARN  lgr.TestLoggerClockSkewWarn lgr: clock moved backwards by 1m0s\n"+
+		"13:01:34 INFO  lgr.TestLoggerClockSkewWarn second\n"+
+		"13:01:34 INFO  lgr.TestLoggerClockSkewWarn third\n", rout.String())
+}
+
+func TestClockMonitorMonotonic(t *testing.T) {
+	c := newClockMonitor()
+	assert.Equal(t, time.Duration(0), c.check(time.Now()))
+
+	c.offset = int64(time.Minute) // wall clock was a minute ahead of monotonic clock before the jump
+	assert.InDelta(t, float64(time.Minute), float64(c.check(time.Now())), float64(time.Second))
+	assert.Equal(t, time.Duration(0), c.check(time.Now()), "no skew after the jump")
+
+	old := time.Now()
+	assert.Equal(t, time.Duration(0), c.check(time.Now().Add(time.Second)))
+	assert.Equal(t, time.Duration(0), c.check(old), "older record checked later is not a skew")
+}
+
+func TestLoggerClockSkewWarnConcurrent(t *testing.T) {
+	rout := &bytes.Buffer{}
+	l := New(Out(rout), ClockSkewWarn, CallerFile)
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 1000; j++ {
+				l.Logf("INFO message %d", j)
+			}
+		}()
+	}
+	wg.Wait()
+	assert.NotContains(t, rout.String(), "clock moved backwards")
+}
@@ -0,0 +1,23 @@
+package lgr
+
+import (
+	"context"
+	"strings"
+)
+
+type ctxLevelKey struct{}
+
+// ContextWithLevel returns context overriding the minimal level for loggers consulting the context, i.e. slog handler
+// made by SlogHandler. Allows request-scoped debug, like ContextWithLevel(r.Context(), "DEBUG") for selected requests.
+func ContextWithLevel(ctx context.Context, level string) context.Context {
+	return context.WithValue(ctx, ctxLevelKey{}, strings.ToUpper(strings.TrimSpace(level)))
+}
+
+// LevelFromContext returns level override set by ContextWithLevel
+func LevelFromContext(ctx context.Context) (string, bool) {
+	if ctx == nil {
+		return "", false
+	}
+	lv, ok := ctx.Value(ctxLevelKey{}).(string)
+	return lv, ok
+}
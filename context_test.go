@@ -0,0 +1,19 @@
+package lgr
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestContextWithLevel(t *testing.T) {
+	_, ok := LevelFromContext(context.Background())
+	assert.False(t, ok)
+	_, ok = LevelFromContext(nil) //nolint:staticcheck // nil context allowed
+	assert.False(t, ok)
+
+	lv, ok := LevelFromContext(ContextWithLevel(context.Background(), " debug"))
+	assert.True(t, ok)
+	assert.Equal(t, "DEBUG", lv)
+}
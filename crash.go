@@ -0,0 +1,63 @@
+package lgr
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+)
+
+// writeCrashReport makes self-contained crash report file in crashDir with the record, environment summary
+// and full stack of all goroutines. The report masked with Secret and Scrub, as args and stack may carry secrets.
+// Errors reported to stderr, as the process is about to exit anyway.
+func (l *Logger) writeCrashReport(ts time.Time, record []byte) {
+	if err := os.MkdirAll(l.crashDir, 0o750); err != nil {
+		_, _ = fmt.Fprintf(l.stderr, "failed to make crash dir %s, %v\n", l.crashDir, err)
+		return
+	}
+
+	fname := filepath.Join(l.crashDir, fmt.Sprintf("crash-%s-%d.log", ts.Format("20060102-150405.000"), os.Getpid()))
+	if err := os.WriteFile(fname, l.maskReport(crashReport(ts, record)), 0o640); err != nil { //nolint:gosec // dir set by user
+		_, _ = fmt.Fprintf(l.stderr, "failed to write crash report %s, %v\n", fname, err)
+	}
+}
+
+// maskReport applies scrubber to each line of the report, dropped lines left out, and hides secrets
+func (l *Logger) maskReport(report []byte) []byte {
+	if l.scrub != nil {
+		lines := strings.Split(string(report), "\n")
+		res := make([]string, 0, len(lines))
+		for _, line := range lines {
+			if scrubbed, keep := l.scrub(line); keep {
+				res = append(res, scrubbed)
+			}
+		}
+		report = []byte(strings.Join(res, "\n"))
+	}
+	return l.hideSecrets(report)
+}
+
+// crashReport renders the content of crash report
+func crashReport(ts time.Time, record []byte) []byte {
+	buf := bytes.Buffer{}
+	hostname, _ := os.Hostname()
+	wd, _ := os.Getwd()
+
+	buf.WriteString("=== crash report ===\n")
+	buf.WriteString("time:       " + ts.Format(time.RFC3339Nano) + "\n")
+	buf.WriteString("record:     " + string(bytes.TrimSuffix(record, []byte("\n"))) + "\n")
+	buf.WriteString("\n=== environment ===\n")
+	buf.WriteString("host:       " + hostname + "\n")
+	buf.WriteString(fmt.Sprintf("pid:        %d\n", os.Getpid()))
+	buf.WriteString("args:       " + strings.Join(os.Args, " ") + "\n")
+	buf.WriteString("workdir:    " + wd + "\n")
+	buf.WriteString("go:         " + runtime.Version() + " " + runtime.GOOS + "/" + runtime.GOARCH + "\n")
+	buf.WriteString(fmt.Sprintf("cpus:       %d, GOMAXPROCS %d\n", runtime.NumCPU(), runtime.GOMAXPROCS(0)))
+	buf.WriteString(fmt.Sprintf("goroutines: %d\n", runtime.NumGoroutine()))
+	buf.WriteString("\n=== stack ===\n")
+	buf.Write(getDump())
+	return buf.Bytes()
+}
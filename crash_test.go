@@ -0,0 +1,80 @@
+package lgr
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoggerCrashDir(t *testing.T) {
+	fatalCalls := 0
+	dir := filepath.Join(t.TempDir(), "crash")
+	rout, rerr := bytes.NewBuffer([]byte{}), bytes.NewBuffer([]byte{})
+	l := New(Out(rout), Err(rerr), CrashDir(dir))
+	l.now = func() time.Time { return time.Date(2018, 1, 7, 13, 2, 34, 0, time.Local) }
+	l.fatal = func() { fatalCalls++ }
+
+	l.Logf("ERROR not a crash")
+	_, err := os.Stat(dir)
+	assert.True(t, os.IsNotExist(err), "no report for ERROR")
+
+	l.Logf("FATAL oh my, fatal error!")
+	assert.Equal(t, 1, fatalCalls)
+
+	files, err := filepath.Glob(filepath.Join(dir, "crash-20180107-130234.000-*.log"))
+	require.NoError(t, err)
+	require.Equal(t, 1, len(files))
+	data, err := os.ReadFile(files[0])
+	require.NoError(t, err)
+	report := string(data)
+	assert.Contains(t, report, "record:     2018/01/07 13:02:34 FATAL oh my, fatal error!\n")
+	assert.Contains(t, report, "go:         "+runtime.Version())
+	assert.Contains(t, report, "=== stack ===\ngoroutine ")
+	assert.Contains(t, report, "github.com/go-pkgz/lgr.TestLoggerCrashDir")
+}
+
+func TestLoggerCrashDirFailed(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "file"), []byte("not a dir"), 0o600))
+	rout, rerr := bytes.NewBuffer([]byte{}), bytes.NewBuffer([]byte{})
+	l := New(Out(rout), Err(rerr), CrashDir(filepath.Join(dir, "file")))
+	l.fatal = func() {}
+	l.Logf("FATAL oh my, fatal error!")
+	assert.Contains(t, rerr.String(), "failed to make crash dir")
+}
+
+func TestLoggerCrashDirMasked(t *testing.T) {
+	args := os.Args
+	os.Args = []string{"app", "--password=secret123", "--user=joe"}
+	defer func() { os.Args = args }()
+
+	dir := t.TempDir()
+	rout, rerr := bytes.NewBuffer([]byte{}), bytes.NewBuffer([]byte{})
+	l := New(Out(rout), Err(rerr), CrashDir(dir), Secret("secret123"), Scrub(func(msg string) (string, bool) {
+		if strings.HasPrefix(msg, "workdir:") {
+			return "", false
+		}
+		return strings.ReplaceAll(msg, "joe", "<user>"), true
+	}))
+	l.fatal = func() {}
+	l.Logf("FATAL oh my, fatal error!")
+
+	files, err := filepath.Glob(filepath.Join(dir, "crash-*.log"))
+	require.NoError(t, err)
+	require.Equal(t, 1, len(files))
+	data, err := os.ReadFile(files[0])
+	require.NoError(t, err)
+	report := string(data)
+	assert.Contains(t, report, "args:       app --password=****** --user=<user>\n")
+	assert.NotContains(t, report, "secret123")
+	assert.NotContains(t, report, "joe")
+	assert.NotContains(t, report, "workdir:", "line dropped by scrubber")
+	assert.Contains(t, report, "record:     ")
+}
@@ -0,0 +1,95 @@
+package lgr
+
+import (
+	"sync"
+	"time"
+)
+
+// Escalation defines policy for repeated WARN and ERROR messages. If the same message logged more than Threshold
+// times within Window it gets escalated: WARN reported as ERROR, and OnEscalate hook called once. Escalation ends
+// with the first window the message doesn't exceed the threshold. Messages compared by the format string,
+// so the same error with different arguments counted together.
+type Escalation struct {
+	Threshold  int                                   // number of the same messages allowed within window
+	Window     time.Duration                         // counting window, a minute if not positive
+	OnEscalate func(level, format string, count int) // optional hook, called when the message escalated
+}
+
+// escalator tracks counts of repeated messages. Shared with child loggers.
+type escalator struct {
+	Escalation
+	lock    sync.Mutex
+	items   map[string]*escalationItem
+	cleaned time.Time // time of the last cleanup
+}
+
+type escalationItem struct {
+	start     time.Time
+	count     int
+	escalated bool
+}
+
+// maxEscalationItems limits tracked messages, expired ones are cleaned when reached, at most once per tenth of
+// the window. New messages not tracked while the limit is still reached.
+const maxEscalationItems = 1000
+
+// defaultEscalationWindow used for Escalation without positive Window
+const defaultEscalationWindow = time.Minute
+
+func newEscalator(e Escalation) *escalator {
+	if e.Window <= 0 {
+		e.Window = defaultEscalationWindow
+	}
+	return &escalator{Escalation: e, items: map[string]*escalationItem{}}
+}
+
+// check counts the message and returns the level to report, escalated if threshold exceeded
+func (e *escalator) check(lv, format string, now time.Time) string {
+	if lv != "WARN" && lv != "ERROR" {
+		return lv
+	}
+
+	key := lv + ":" + format
+	e.lock.Lock()
+	item, ok := e.items[key]
+	if !ok {
+		if len(e.items) >= maxEscalationItems && now.Sub(e.cleaned) >= e.Window/10 {
+			e.cleanup(now)
+		}
+		if len(e.items) >= maxEscalationItems {
+			e.lock.Unlock()
+			return lv
+		}
+		item = &escalationItem{start: now}
+		e.items[key] = item
+	}
+	if elapsed := now.Sub(item.start); elapsed > e.Window {
+		// stays escalated only if the message exceeded the threshold in the window just ended, without a gap after it
+		item.escalated = item.escalated && item.count > e.Threshold && elapsed < 2*e.Window
+		item.start, item.count = now, 0
+	}
+	item.count++
+	escalated, justEscalated, count := item.escalated, false, item.count
+	if !item.escalated && item.count > e.Threshold {
+		item.escalated, escalated, justEscalated = true, true, true
+	}
+	e.lock.Unlock()
+
+	if justEscalated && e.OnEscalate != nil {
+		e.OnEscalate(lv, format, count)
+	}
+	if escalated && lv == "WARN" {
+		return "ERROR"
+	}
+	return lv
+}
+
+// cleanup removes expired items, escalated ones kept for the next window, called under lock
+func (e *escalator) cleanup(now time.Time) {
+	e.cleaned = now
+	for k, v := range e.items {
+		if elapsed := now.Sub(v.start); elapsed >= 2*e.Window || (elapsed > e.Window && !v.escalated) {
+			delete(e.items, k)
+		}
+	}
+}
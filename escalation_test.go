@@ -0,0 +1,112 @@
+package lgr
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoggerEscalate(t *testing.T) {
+	var escalated []string
+	rout, rerr := bytes.NewBuffer([]byte{}), bytes.NewBuffer([]byte{})
+	ts := time.Date(2018, 1, 7, 13, 2, 34, 0, time.Local)
+	l := New(Out(rout), Err(rerr), Format(`{{.Level}} {{.Message}}`), Escalate(Escalation{
+		Threshold: 2,
+		Window:    time.Minute,
+		OnEscalate: func(level, format string, count int) {
+			escalated = append(escalated, fmt.Sprintf("%s|%s|%d", level, format, count))
+		},
+	}))
+	l.now = func() time.Time { return ts }
+
+	for i := 0; i < 4; i++ {
+		l.Logf("WARN disk is almost full, %d%%", 90+i)
+	}
+	assert.Equal(t, "WARN  disk is almost full, 90%\nWARN  disk is almost full, 91%\n"+
+		"ERROR disk is almost full, 92%\nERROR disk is almost full, 93%\n", rout.String())
+	assert.Equal(t, "ERROR disk is almost full, 92%\nERROR disk is almost full, 93%\n", rerr.String())
+	assert.Equal(t, []string{"WARN|WARN disk is almost full, %d%%|3"}, escalated)
+
+	rout.Reset()
+	for i := 0; i < 3; i++ {
+		l.Logf("ERROR can't connect to %s", "db")
+	}
+	l.Logf("INFO not counted")
+	l.Logf("INFO not counted")
+	l.Logf("INFO not counted")
+	assert.Equal(t, 6, bytes.Count(rout.Bytes(), []byte("\n")))
+	require.Equal(t, 2, len(escalated))
+	assert.Equal(t, "ERROR|ERROR can't connect to %s|3", escalated[1])
+
+	rout.Reset()
+	ts = ts.Add(2 * time.Minute) // next window, de-escalated
+	l.Logf("WARN disk is almost full, %d%%", 95)
+	assert.Equal(t, "WARN  disk is almost full, 95%\n", rout.String())
+}
+
+func TestEscalatorCleanup(t *testing.T) {
+	e := newEscalator(Escalation{Threshold: 1, Window: time.Second})
+	ts := time.Date(2018, 1, 7, 13, 2, 34, 0, time.Local)
+	for i := 0; i < maxEscalationItems; i++ {
+		e.check("WARN", fmt.Sprintf("msg %d", i), ts)
+	}
+	assert.Equal(t, maxEscalationItems, len(e.items))
+	assert.Equal(t, "WARN", e.check("WARN", "new one", ts.Add(time.Minute)))
+	assert.Equal(t, 1, len(e.items), "expired removed")
+	assert.Equal(t, "INFO", e.check("INFO", "new one", ts))
+}
+
+func TestLoggerEscalateContinuingBurst(t *testing.T) {
+	var escalated int
+	rout := bytes.NewBuffer([]byte{})
+	ts := time.Date(2018, 1, 7, 13, 2, 34, 0, time.Local)
+	l := New(Out(rout), Err(rout), Format(`{{.Level}} {{.Message}}`), Escalate(Escalation{
+		Threshold: 2, Window: time.Minute, OnEscalate: func(string, string, int) { escalated++ },
+	}))
+	l.now = func() time.Time { return ts }
+
+	burst := func(n int) string {
+		rout.Reset()
+		for i := 0; i < n; i++ {
+			l.Logf("WARN retrying")
+			ts = ts.Add(10 * time.Second)
+		}
+		return rout.String()
+	}
+
+	assert.Equal(t, "WARN  retrying\nWARN  retrying\nERROR retrying\nERROR retrying\nERROR retrying\nERROR retrying\n", burst(6))
+	assert.Equal(t, "ERROR retrying\nERROR retrying\n", burst(2), "second window, still escalated")
+	assert.Equal(t, 1, escalated, "hook called once for the whole burst")
+
+	ts = ts.Add(time.Minute) // the second window ended without exceeding the threshold
+	assert.Equal(t, "WARN  retrying\n", burst(1))
+}
+
+func TestEscalatorDefaultWindow(t *testing.T) {
+	e := newEscalator(Escalation{Threshold: 1})
+	assert.Equal(t, time.Minute, e.Window)
+	ts := time.Date(2018, 1, 7, 13, 2, 34, 0, time.Local)
+	assert.Equal(t, "WARN", e.check("WARN", "msg", ts))
+	assert.Equal(t, "ERROR", e.check("WARN", "msg", ts.Add(time.Second)))
+}
+
+func TestEscalatorItemsLimit(t *testing.T) {
+	e := newEscalator(Escalation{Threshold: 1, Window: time.Minute})
+	ts := time.Date(2018, 1, 7, 13, 2, 34, 0, time.Local)
+	for i := 0; i < 5*maxEscalationItems; i++ {
+		e.check("WARN", fmt.Sprintf("msg %d", i), ts.Add(time.Duration(i)*time.Millisecond))
+	}
+	assert.Equal(t, maxEscalationItems, len(e.items), "limited within the window")
+
+	assert.Equal(t, "WARN", e.check("WARN", "new one", ts.Add(10*time.Second)))
+	assert.Equal(t, "WARN", e.check("WARN", "new one", ts.Add(10*time.Second)), "not tracked while full")
+	assert.Equal(t, "ERROR", e.check("WARN", "msg 1", ts.Add(10*time.Second)), "tracked one still escalated")
+
+	assert.Equal(t, "WARN", e.check("WARN", "new one", ts.Add(2*time.Minute)))
+	assert.Equal(t, "ERROR", e.check("WARN", "new one", ts.Add(2*time.Minute)), "tracked after expired cleaned")
+	assert.Equal(t, 2, len(e.items), "escalated one kept for the next window")
+}
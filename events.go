@@ -0,0 +1,64 @@
+package lgr
+
+import "strings"
+
+// eventLocales keeps localized message catalogs, locale -> event id -> format
+type eventLocales map[string]map[string]string
+
+// Events sets message catalog, event id -> printf-style format with optional level prefix,
+// i.e. {"user_login": "INFO user %s logged in from %s"}. Messages of the catalog logged with Event.
+func Events(catalog map[string]string) Option {
+	return func(l *Logger) {
+		if l.events == nil {
+			l.events = map[string]string{}
+		}
+		for id, format := range catalog {
+			l.events[id] = format
+		}
+	}
+}
+
+// EventsLocale sets localized message catalog for the locale, i.e. EventsLocale("de", {"user_login": "INFO Benutzer %s
+// angemeldet von %s"}). Used instead of the default catalog for loggers with the locale set by Locale option,
+// events missing in the localized catalog taken from the default one. Event ids stay the same for all locales.
+func EventsLocale(locale string, catalog map[string]string) Option {
+	return func(l *Logger) {
+		if l.localeEvents == nil {
+			l.localeEvents = eventLocales{}
+		}
+		if l.localeEvents[locale] == nil {
+			l.localeEvents[locale] = map[string]string{}
+		}
+		for id, format := range catalog {
+			l.localeEvents[locale][id] = format
+		}
+	}
+}
+
+// Locale sets locale of the message catalog used by Event, see EventsLocale
+func Locale(locale string) Option {
+	return func(l *Logger) {
+		l.locale = locale
+	}
+}
+
+// Event logs message of the catalog set with Events, i.e. l.Event("user_login", user, ip). The event id always
+// added to the message as a tag, i.e. "#user_login", so it can be muted, routed and parsed like other tags,
+// and as "event" field, i.e. "event=user_login", so it's kept by every format, JSON included.
+// Unknown event logged at WARN level with all arguments.
+func (l *Logger) Event(id string, args ...interface{}) {
+	id = strings.TrimPrefix(strings.TrimSpace(id), "#")
+	c := *l // event field set on a shallow copy, the same way as Logw pairs
+	c.fields = append(make(Fields, 0, len(l.fields)+1), l.fields...).set("event", id)
+
+	format, ok := l.localeEvents[l.locale][id]
+	if !ok {
+		format, ok = l.events[id]
+	}
+	if !ok {
+		c.logf("WARN #%s unknown event %v", id, args)
+		return
+	}
+	lv, msg := ParseLevel(format)
+	c.logf(lv+" #"+id+" "+msg, args...)
+}
@@ -0,0 +1,92 @@
+package lgr
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoggerEvent(t *testing.T) {
+	tbl := []struct {
+		id   string
+		args []interface{}
+		res  string
+	}{
+		{"user_login", []interface{}{"joe", "127.0.0.1"}, "INFO  #user_login user joe logged in from 127.0.0.1 event=user_login\n"},
+		{"#user_login", []interface{}{"joe", "::1"}, "INFO  #user_login user joe logged in from ::1 event=user_login\n"},
+		{"disk_full", []interface{}{95}, "WARN  #disk_full disk usage 95% event=disk_full\n"},
+		{"started", nil, "INFO  #started service started event=started\n"},
+		{"unknown", []interface{}{1, "a"}, "WARN  #unknown unknown event [1 a] event=unknown\n"},
+	}
+
+	rout, rerr := bytes.NewBuffer([]byte{}), bytes.NewBuffer([]byte{})
+	l := New(Out(rout), Err(rerr), Format(`{{.Level}} {{.Tags}} {{.Message}}`),
+		Events(map[string]string{"user_login": "INFO user %s logged in from %s", "disk_full": "[WARN] disk usage %d%%"}),
+		Events(map[string]string{"started": "service started"}))
+
+	for _, tt := range tbl {
+		t.Run(tt.id, func(t *testing.T) {
+			rout.Reset()
+			l.Event(tt.id, tt.args...)
+			assert.Equal(t, tt.res, rout.String())
+		})
+	}
+}
+
+func TestLoggerEventCaller(t *testing.T) {
+	rout := bytes.NewBuffer([]byte{})
+	l := New(Out(rout), Events(map[string]string{"ev": "INFO event"}), MuteTags("muted"),
+		Format(`{{.CallerFunc}} {{.Tags}} {{.Message}}`))
+	l.Event("ev")
+	assert.Equal(t, "lgr.TestLoggerEventCaller #ev event event=ev\n", rout.String())
+
+	rout.Reset()
+	l.Tagged("muted").Event("ev")
+	assert.Equal(t, "", rout.String(), "muted by tag")
+}
+
+func TestLoggerEventLocale(t *testing.T) {
+	rout := bytes.NewBuffer([]byte{})
+	catalog := []Option{
+		Events(map[string]string{"user_login": "INFO user %s logged in", "started": "INFO service started"}),
+		EventsLocale("de", map[string]string{"user_login": "INFO Benutzer %s angemeldet"}),
+		EventsLocale("fr", map[string]string{"user_login": "INFO utilisateur %s connecté"}),
+		Format(`{{.Level}} {{.Tags}} {{.Message}}`),
+	}
+
+	l := New(append(catalog, Out(rout), Locale("de"))...)
+	l.Event("user_login", "joe")
+	l.Event("started")
+	assert.Equal(t, "INFO  #user_login Benutzer joe angemeldet event=user_login\nINFO  #started service started event=started\n", rout.String())
+
+	rout.Reset()
+	l = New(append(catalog, Out(rout))...)
+	l.Event("user_login", "joe")
+	assert.Equal(t, "INFO  #user_login user joe logged in event=user_login\n", rout.String(), "default catalog without locale")
+
+	rout.Reset()
+	l = New(append(catalog, Out(rout), Locale("es"))...)
+	l.Event("user_login", "joe")
+	assert.Equal(t, "INFO  #user_login user joe logged in event=user_login\n", rout.String(), "default catalog for unknown locale")
+}
+
+func TestLoggerEventFormats(t *testing.T) {
+	rout := bytes.NewBuffer([]byte{})
+	events := Events(map[string]string{"user_login": "INFO user %s logged in"})
+
+	l := New(Out(rout), Format(Short), events)
+	l.Event("user_login", "joe")
+	assert.Contains(t, rout.String(), " INFO  #user_login user joe logged in event=user_login\n")
+
+	rout.Reset()
+	l = New(Out(rout), Format(GCP), events).With("user", "joe")
+	l.Event("user_login", "joe")
+	rec := map[string]interface{}{}
+	require.NoError(t, json.Unmarshal(rout.Bytes(), &rec), rout.String())
+	assert.Equal(t, "user_login", rec["event"])
+	assert.Equal(t, "joe", rec["user"], "logger's own fields kept")
+	assert.Equal(t, "#user_login user joe logged in", rec["message"])
+}
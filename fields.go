@@ -0,0 +1,152 @@
+package lgr
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Field is a key-value pair added to each record of the child logger made by With or WithFields
+type Field struct {
+	Key string
+	Val interface{}
+}
+
+// Fields is a list of fields in the order of addition, rendered as logfmt key=value pairs by String
+type Fields []Field
+
+// With makes child logger adding the field to each record. Child shares writers and options with the parent.
+// Field with the same key as one of the parent's fields replaces it.
+// Fields rendered by Logfmt and GCP formats as separate keys, other formats get " key=value" pairs after the message.
+func (l *Logger) With(key string, val interface{}) *Logger {
+	return l.WithFields(map[string]interface{}{key: val})
+}
+
+// WithFields makes child logger adding fields to each record, new fields sorted by key. See With for details.
+func (l *Logger) WithFields(fields map[string]interface{}) *Logger {
+	keys := make([]string, 0, len(fields))
+	for k := range fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	res := l.clone()
+	res.fields = make(Fields, len(l.fields), len(l.fields)+len(keys))
+	copy(res.fields, l.fields)
+	for _, k := range keys {
+		res.fields = res.fields.set(k, fields[k])
+	}
+	return res
+}
+
+// Logw logs message at the level with key-value pairs, i.e. Logw("INFO", "request done", "status", 200, "dur", d).
+// Pairs rendered as fields, after fields of With, and a key of With field replaced by the pair with the same key.
+// Non-string keys formatted with fmt.Sprint and value without a key reported with "!BADKEY" key. Unknown level is INFO.
+func (l *Logger) Logw(level, msg string, kvs ...interface{}) { l.logw(level, msg, kvs) }
+
+// Debugw logs message with key-value pairs at DEBUG level, see Logw
+func (l *Logger) Debugw(msg string, kvs ...interface{}) { l.logw("DEBUG", msg, kvs) }
+
+// Infow logs message with key-value pairs at INFO level, see Logw
+func (l *Logger) Infow(msg string, kvs ...interface{}) { l.logw("INFO", msg, kvs) }
+
+// Warnw logs message with key-value pairs at WARN level, see Logw
+func (l *Logger) Warnw(msg string, kvs ...interface{}) { l.logw("WARN", msg, kvs) }
+
+// Errorw logs message with key-value pairs at ERROR level, see Logw
+func (l *Logger) Errorw(msg string, kvs ...interface{}) { l.logw("ERROR", msg, kvs) }
+
+func (l *Logger) logw(level, msg string, kvs []interface{}) {
+	if l.noop {
+		return
+	}
+	lv := pairsLevel(level)
+	if len(kvs) == 0 {
+		l.log(lv, msg, msg, 0)
+		return
+	}
+
+	c := *l // per-call fields set on a shallow copy, the same way as per-request level of slog handler
+	c.fields = make(Fields, len(l.fields), len(l.fields)+(len(kvs)+1)/2)
+	copy(c.fields, l.fields)
+	c.fields = c.fields.pairs(kvs)
+	c.log(lv, msg, msg, 0)
+}
+
+// pairsLevel normalizes level of Logw, unknown level is INFO
+func pairsLevel(level string) string {
+	lv := strings.ToUpper(strings.TrimSpace(level))
+	if levelIndex(lv) < 0 {
+		return "INFO"
+	}
+	return lv
+}
+
+// scrubFields applies scrubber to field values formatted with fmt.Sprint, unchanged values kept as is.
+// Returns false if scrubber dropped any value.
+func (l *Logger) scrubFields() (Fields, bool) {
+	if len(l.fields) == 0 {
+		return l.fields, true
+	}
+	res := make(Fields, len(l.fields))
+	for i, f := range l.fields {
+		val := fmt.Sprint(f.Val)
+		scrubbed, keep := l.scrub(val)
+		if !keep {
+			return nil, false
+		}
+		res[i] = f
+		if scrubbed != val {
+			res[i].Val = scrubbed
+		}
+	}
+	return res, true
+}
+
+// set replaces value of the field with the key or appends a new field
+func (f Fields) set(key string, val interface{}) Fields {
+	for i := range f {
+		if f[i].Key == key {
+			f[i].Val = val
+			return f
+		}
+	}
+	return append(f, Field{Key: key, Val: val})
+}
+
+// pairs sets fields from key-value pairs of Logw, see Logw for handling of bad keys
+func (f Fields) pairs(kvs []interface{}) Fields {
+	for i := 0; i < len(kvs); i += 2 {
+		if i+1 == len(kvs) {
+			return f.set("!BADKEY", kvs[i])
+		}
+		key, ok := kvs[i].(string)
+		if !ok {
+			key = fmt.Sprint(kvs[i])
+		}
+		f = f.set(key, kvs[i+1])
+	}
+	return f
+}
+
+// String returns fields as logfmt key=value pairs separated by space, i.e. `user=joe path="/a b"`
+func (f Fields) String() string {
+	parts := make([]string, 0, len(f))
+	for _, fld := range f {
+		parts = append(parts, logfmtValue(fld.Key)+"="+logfmtValue(fmt.Sprint(fld.Val)))
+	}
+	return strings.Join(parts, " ")
+}
+
+// jsonValue makes JSON value, errors reported as strings and values failing to marshal as their fmt.Sprint form
+func jsonValue(v interface{}) string {
+	if err, ok := v.(error); ok {
+		return jsonString(err.Error())
+	}
+	res, err := json.Marshal(v)
+	if err != nil {
+		return jsonString(fmt.Sprint(v))
+	}
+	return string(res)
+}
@@ -0,0 +1,117 @@
+package lgr
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoggerWith(t *testing.T) {
+	tbl := []struct {
+		name   string
+		format string
+		res    string
+	}{
+		{"options", "", "2018/01/07 13:02:34 INFO  message 123 user=joe id=42 path=\"/a b\"\n"},
+		{"template", `{{.Level}} {{.Message}}`, "INFO  message 123 user=joe id=42 path=\"/a b\"\n"},
+		{"template with fields", `{{.Level}} [{{.Fields}}] {{.Message}}`, "INFO  [user=joe id=42 path=\"/a b\"] message 123\n"},
+		{"logfmt", Logfmt, "time=2018-01-07T13:02:34.000Z level=info caller=lgr/fields_test.go:33 func=lgr.TestLoggerWith.func1 " +
+			"msg=\"message 123\" user=joe id=42 path=\"/a b\"\n"},
+	}
+
+	for _, tt := range tbl {
+		t.Run(tt.name, func(t *testing.T) {
+			buf := bytes.Buffer{}
+			l := New(Out(&buf), Format(tt.format))
+			l.now = func() time.Time { return time.Date(2018, 1, 7, 13, 2, 34, 0, time.UTC) }
+			l.With("user", "joe").WithFields(map[string]interface{}{"path": "/a b", "id": 42}).Logf("INFO message %d", 123)
+			assert.Equal(t, tt.res, buf.String())
+		})
+	}
+}
+
+func TestLoggerWithGCP(t *testing.T) {
+	buf := bytes.Buffer{}
+	l := New(Out(&buf), Format(GCP)).With("user", "joe").With("err", errors.New("oops")).
+		WithFields(map[string]interface{}{"n": 1.5, "tags": []string{"a", "b"}, "ch": make(chan int)})
+	l.Logf("WARN message")
+
+	rec := map[string]interface{}{}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &rec), buf.String())
+	assert.Equal(t, "message", rec["message"])
+	assert.Equal(t, "joe", rec["user"])
+	assert.Equal(t, "oops", rec["err"])
+	assert.Equal(t, 1.5, rec["n"])
+	assert.Equal(t, []interface{}{"a", "b"}, rec["tags"])
+	assert.Contains(t, rec["ch"], "0x", "not marshaled value as string")
+}
+
+func TestLoggerWithChild(t *testing.T) {
+	buf := bytes.Buffer{}
+	parent := New(Out(&buf), Format(`{{.Message}}`)).With("a", 1)
+	child := parent.With("b", 2).With("a", "replaced")
+	_ = parent.With("c", 3)
+
+	parent.Logf("parent")
+	child.Logf("child")
+	assert.Equal(t, "parent a=1\nchild a=replaced b=2\n", buf.String())
+}
+
+func TestLoggerLogw(t *testing.T) {
+	tbl := []struct {
+		name string
+		fn   func(l *Logger)
+		res  string
+	}{
+		{"logw", func(l *Logger) { l.Logw("warn", "message", "k", 1, "s", "a b") }, "WARN  message user=joe k=1 s=\"a b\"\n"},
+		{"unknown level", func(l *Logger) { l.Logw("BLAH", "message") }, "INFO  message user=joe\n"},
+		{"debug", func(l *Logger) { l.Debugw("message", "k", 1) }, "DEBUG message user=joe k=1\n"},
+		{"info", func(l *Logger) { l.Infow("message", "user", "replaced") }, "INFO  message user=replaced\n"},
+		{"warn", func(l *Logger) { l.Warnw("message", 1, 2) }, "WARN  message user=joe 1=2\n"},
+		{"error", func(l *Logger) { l.Errorw("failed", "err", errors.New("oops"), "dangling") },
+			"ERROR failed user=joe err=oops !BADKEY=dangling\n"},
+		{"message with percent", func(l *Logger) { l.Infow("100%s done") }, "INFO  100%s done user=joe\n"},
+	}
+
+	for _, tt := range tbl {
+		t.Run(tt.name, func(t *testing.T) {
+			buf := bytes.Buffer{}
+			l := New(Out(&buf), Err(&buf), Debug, Format(`{{.Level}} {{.Message}}`)).With("user", "joe")
+			tt.fn(l)
+			assert.Equal(t, tt.res, buf.String())
+		})
+	}
+}
+
+func TestLoggerLogwCaller(t *testing.T) {
+	buf := bytes.Buffer{}
+	l := New(Out(&buf), Format(Logfmt))
+	l.Infow("message", "k", "v")
+	l.Logw("INFO", "message")
+	assert.Contains(t, buf.String(), "caller=lgr/fields_test.go:95 func=lgr.TestLoggerLogwCaller msg=message k=v\n")
+	assert.Contains(t, buf.String(), "caller=lgr/fields_test.go:96 func=lgr.TestLoggerLogwCaller msg=message\n")
+
+	NoOpLogger.Infow("message", "k", "v") // no panic
+}
+
+func TestLoggerWithScrub(t *testing.T) {
+	buf := bytes.Buffer{}
+	l := New(Out(&buf), Format(Logfmt), Scrub(func(msg string) (string, bool) {
+		return strings.ReplaceAll(msg, "joe@example.com", "<email>"), !strings.Contains(msg, "forgotten")
+	}))
+	l.now = func() time.Time { return time.Date(2018, 1, 7, 13, 2, 34, 0, time.UTC) }
+
+	l.With("email", "joe@example.com").Infow("login", "id", 42, "note", "by joe@example.com")
+	assert.Contains(t, buf.String(), `msg=login email=<email> id=42 note="by <email>"`)
+
+	buf.Reset()
+	l.With("user", "forgotten").Logf("INFO login")
+	l.Infow("login", "user", "forgotten")
+	assert.Equal(t, "", buf.String(), "dropped by scrubbed field")
+}
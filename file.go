@@ -0,0 +1,221 @@
+package lgr
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"regexp"
+	"sync"
+	"syscall"
+	"unicode/utf8"
+)
+
+// ReopenFile is io.Writer appending to a file which can be reopened at any time, i.e. after external rotation
+// by logrotate. Thread safe.
+type ReopenFile struct {
+	path string
+	bom  bool // write UTF-8 BOM to new (empty) files
+	lock sync.Mutex
+	file *os.File
+}
+
+// FileOption func type for OpenFile
+type FileOption func(f *ReopenFile)
+
+// FileBOM writes UTF-8 byte order mark to each new (empty) file, for Windows tools requiring it
+func FileBOM(f *ReopenFile) {
+	f.bom = true
+}
+
+// OpenFile makes ReopenFile for given path, creating the file if it doesn't exist
+func OpenFile(path string, opts ...FileOption) (*ReopenFile, error) {
+	res := ReopenFile{path: path}
+	for _, opt := range opts {
+		opt(&res)
+	}
+	if err := res.Reopen(); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// Write to the currently opened file
+func (f *ReopenFile) Write(p []byte) (n int, err error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	if f.file == nil {
+		return 0, fmt.Errorf("file %s is closed", f.path)
+	}
+	return f.file.Write(p)
+}
+
+// Reopen closes the current file and opens it again by the same path.
+// Should be called after the file was moved away by external rotation.
+func (f *ReopenFile) Reopen() error {
+	fh, err := os.OpenFile(f.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o640) //nolint:gosec // path set by user
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", f.path, err)
+	}
+	if f.bom {
+		if st, err := fh.Stat(); err == nil && st.Size() == 0 {
+			if _, err = fh.Write(utf8BOM); err != nil {
+				_ = fh.Close()
+				return fmt.Errorf("failed to write BOM to %s: %w", f.path, err)
+			}
+		}
+	}
+
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	if f.file != nil {
+		_ = f.file.Close()
+	}
+	f.file = fh
+	return nil
+}
+
+// ReopenOnSignal reopens the file each time one of signals received, SIGHUP by default.
+// Reopen errors are reported to stderr. Returned func stops signal handling.
+func (f *ReopenFile) ReopenOnSignal(sigs ...os.Signal) (stop func()) {
+	if len(sigs) == 0 {
+		sigs = []os.Signal{syscall.SIGHUP}
+	}
+	sigCh := make(chan os.Signal, 1)
+	done := make(chan struct{})
+	signal.Notify(sigCh, sigs...)
+
+	go func() {
+		for {
+			select {
+			case <-sigCh:
+				if err := f.Reopen(); err != nil {
+					fmt.Fprintf(os.Stderr, "failed to reopen log file, %v\n", err)
+				}
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			signal.Stop(sigCh)
+			close(done)
+		})
+	}
+}
+
+// Close the file. Writes after Close will fail until Reopen called.
+func (f *ReopenFile) Close() error {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	if f.file == nil {
+		return nil
+	}
+	err := f.file.Close()
+	f.file = nil
+	return err
+}
+
+var utf8BOM = []byte{0xEF, 0xBB, 0xBF}
+
+// asciiWriter escapes non-ASCII characters
+type asciiWriter struct {
+	w io.Writer
+}
+
+// ASCIIWriter makes writer escaping non-ASCII characters as \uXXXX (\UXXXXXXXX above the BMP), for legacy consumers
+// failing on raw UTF-8. Invalid UTF-8 bytes escaped as \xXX.
+func ASCIIWriter(w io.Writer) io.Writer {
+	return &asciiWriter{w: w}
+}
+
+// Write escaped data, returns len(p) on success as escaping changes the size
+func (a *asciiWriter) Write(p []byte) (int, error) {
+	if isASCII(p) {
+		return a.w.Write(p)
+	}
+	buf := make([]byte, 0, len(p)+len(p)/2)
+	for i := 0; i < len(p); {
+		if p[i] < utf8.RuneSelf {
+			buf = append(buf, p[i])
+			i++
+			continue
+		}
+		r, size := utf8.DecodeRune(p[i:])
+		switch {
+		case r == utf8.RuneError && size == 1:
+			buf = append(buf, fmt.Sprintf("\\x%02x", p[i])...)
+		case r > 0xFFFF:
+			buf = append(buf, fmt.Sprintf("\\U%08x", r)...)
+		default:
+			buf = append(buf, fmt.Sprintf("\\u%04x", r)...)
+		}
+		i += size
+	}
+	if _, err := a.w.Write(buf); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// Unwrap returns the underlying writer
+func (a *asciiWriter) Unwrap() io.Writer { return a.w }
+
+// MultiWriter makes writer duplicating writes to all writers, as io.MultiWriter does, and exposing them with
+// Unwrap() []io.Writer. Unlike io.MultiWriter, detected as the same stream as err writer it includes, i.e.
+// Out(lgr.MultiWriter(os.Stdout, f)) with Err(os.Stdout) writes ERROR records to stdout once.
+func MultiWriter(writers ...io.Writer) io.Writer {
+	ws := make([]io.Writer, len(writers))
+	copy(ws, writers)
+	return &multiWriter{Writer: io.MultiWriter(ws...), writers: ws}
+}
+
+// multiWriter is io.MultiWriter with writers exposed
+type multiWriter struct {
+	io.Writer
+	writers []io.Writer
+}
+
+// Unwrap returns the underlying writers
+func (m *multiWriter) Unwrap() []io.Writer { return m.writers }
+
+func isASCII(p []byte) bool {
+	for _, b := range p {
+		if b >= utf8.RuneSelf {
+			return false
+		}
+	}
+	return true
+}
+
+// reANSI matches ANSI CSI sequences, i.e. colors "\x1b[31m", and OSC sequences, i.e. hyperlinks
+var reANSI = regexp.MustCompile(`\x1b\[[0-9;?]*[ -/]*[@-~]|\x1b\][^\x07\x1b]*(?:\x07|\x1b\\)`)
+
+// ansiStripWriter removes ANSI escape sequences
+type ansiStripWriter struct {
+	w io.Writer
+}
+
+// StripANSI makes writer removing ANSI escape sequences, i.e. colors added by Mapper, so a colored logger can feed
+// both a terminal and a file. Sequences expected to be complete in each write, as lgr writes whole records.
+func StripANSI(w io.Writer) io.Writer {
+	return &ansiStripWriter{w: w}
+}
+
+// Write data without escape sequences, returns len(p) on success as stripping changes the size
+func (a *ansiStripWriter) Write(p []byte) (int, error) {
+	if bytes.IndexByte(p, 0x1b) < 0 {
+		return a.w.Write(p)
+	}
+	if _, err := a.w.Write(reANSI.ReplaceAll(p, nil)); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// Unwrap returns the underlying writer
+func (a *ansiStripWriter) Unwrap() io.Writer { return a.w }
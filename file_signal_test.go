@@ -0,0 +1,51 @@
+//go:build !windows
+
+package lgr
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReopenFile_OnSignal(t *testing.T) {
+	dir := t.TempDir()
+	fname := filepath.Join(dir, "app.log")
+	f, err := OpenFile(fname)
+	require.NoError(t, err)
+	defer f.Close()
+
+	stop := f.ReopenOnSignal(syscall.SIGUSR1)
+	defer stop()
+
+	_, err = f.Write([]byte("line1\n"))
+	require.NoError(t, err)
+	require.NoError(t, os.Rename(fname, fname+".1"))
+	require.NoError(t, syscall.Kill(os.Getpid(), syscall.SIGUSR1))
+
+	// file created by reopen before the handle swapped, so keep writing until a write lands in the new file
+	reopened := false
+	for deadline := time.Now().Add(time.Second); !reopened && time.Now().Before(deadline); {
+		_, err = f.Write([]byte("line2\n"))
+		require.NoError(t, err)
+		data, rerr := os.ReadFile(fname)
+		reopened = rerr == nil && len(data) > 0
+		time.Sleep(time.Millisecond)
+	}
+	require.True(t, reopened, "file reopened")
+
+	data, err := os.ReadFile(fname)
+	require.NoError(t, err)
+	assert.Equal(t, "line2\n", string(data))
+	old, err := os.ReadFile(fname + ".1")
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(string(old), "line1\n"))
+	rest := strings.TrimPrefix(string(old), "line1\n")
+	assert.Equal(t, strings.Repeat("line2\n", strings.Count(rest, "\n")), rest, "written before the swap")
+}
@@ -0,0 +1,168 @@
+package lgr
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReopenFile(t *testing.T) {
+	dir := t.TempDir()
+	fname := filepath.Join(dir, "app.log")
+	f, err := OpenFile(fname)
+	require.NoError(t, err)
+
+	l := New(Out(f), Err(f), Format(Short))
+	l.now = func() time.Time { return time.Date(2018, 1, 7, 13, 2, 34, 0, time.Local) }
+	l.Logf("INFO first line")
+
+	require.NoError(t, os.Rename(fname, fname+".1")) // external rotation
+	l.Logf("INFO second line")                       // still goes to the moved file
+	require.NoError(t, f.Reopen())
+	l.Logf("INFO third line")
+	require.NoError(t, f.Close())
+
+	data, err := os.ReadFile(fname + ".1")
+	require.NoError(t, err)
+	assert.Equal(t, "2018/01/07 13:02:34 INFO  first line\n2018/01/07 13:02:34 INFO  second line\n", string(data))
+
+	data, err = os.ReadFile(fname)
+	require.NoError(t, err)
+	assert.Equal(t, "2018/01/07 13:02:34 INFO  third line\n", string(data))
+
+	_, err = f.Write([]byte("closed"))
+	assert.Error(t, err)
+}
+
+func TestOpenFile_Error(t *testing.T) {
+	_, err := OpenFile(filepath.Join(t.TempDir(), "no-such-dir", "app.log"))
+	assert.Error(t, err)
+}
+
+func TestIsStreamsSameResolved(t *testing.T) {
+	fname := filepath.Join(t.TempDir(), "test.log")
+	r1, err := OpenFile(fname)
+	require.NoError(t, err)
+	defer r1.Close()
+	r2, err := OpenFile(fname)
+	require.NoError(t, err)
+	defer r2.Close()
+	f, err := os.Open(fname)
+	require.NoError(t, err)
+	defer f.Close()
+
+	assert.True(t, isStreamsSame(r1, r2), "reopen files with the same path")
+	assert.True(t, isStreamsSame(r1, f), "reopen file and file")
+	assert.False(t, isStreamsSame(r1, os.Stdout))
+
+	buf := bytes.NewBuffer([]byte{})
+	assert.True(t, isStreamsSame(unwrappingWriter{buf}, buf), "unwrapped writer")
+	assert.False(t, isStreamsSame(unwrappingWriter{buf}, bytes.NewBuffer([]byte{})))
+
+	assert.False(t, isStreamsSame(sliceWriter{}, sliceWriter{}), "non-comparable writers, no panic")
+
+	out := bytes.NewBuffer([]byte{})
+	l := New(Out(unwrappingWriter{out}), Err(out), Format(`{{.Level}} {{.Message}}`))
+	l.Logf("ERROR failed")
+	assert.Equal(t, "ERROR failed\n", out.String(), "no duplicate for the same stream")
+}
+
+type unwrappingWriter struct{ w io.Writer }
+
+func (u unwrappingWriter) Write(p []byte) (int, error) { return u.w.Write(p) }
+func (u unwrappingWriter) Unwrap() io.Writer           { return u.w }
+
+type sliceWriter struct{ data []byte }
+
+func (s sliceWriter) Write(p []byte) (int, error) { return len(p), nil }
+
+func TestOpenFile_BOM(t *testing.T) {
+	fname := filepath.Join(t.TempDir(), "test.log")
+	f, err := OpenFile(fname, FileBOM)
+	require.NoError(t, err)
+	_, err = f.Write([]byte("line 1\n"))
+	require.NoError(t, err)
+	require.NoError(t, f.Reopen())
+	_, err = f.Write([]byte("line 2\n"))
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+
+	data, err := os.ReadFile(fname)
+	require.NoError(t, err)
+	assert.Equal(t, "\xEF\xBB\xBFline 1\nline 2\n", string(data), "BOM written once, for the new file")
+}
+
+func TestASCIIWriter(t *testing.T) {
+	tbl := []struct {
+		inp, out string
+	}{
+		{"plain text\n", "plain text\n"},
+		{"привет", `\u043f\u0440\u0438\u0432\u0435\u0442`},
+		{"café ok", `caf\u00e9 ok`},
+		{"emoji 😀", `emoji \U0001f600`},
+		{"bad \xff byte", `bad \xff byte`},
+	}
+
+	for _, tt := range tbl {
+		t.Run(tt.inp, func(t *testing.T) {
+			buf := bytes.Buffer{}
+			n, err := ASCIIWriter(&buf).Write([]byte(tt.inp))
+			require.NoError(t, err)
+			assert.Equal(t, len(tt.inp), n)
+			assert.Equal(t, tt.out, buf.String())
+		})
+	}
+}
+
+func TestStripANSI(t *testing.T) {
+	tbl := []struct {
+		inp, out string
+	}{
+		{"plain text\n", "plain text\n"},
+		{"\x1b[31mERROR\x1b[0m failed\n", "ERROR failed\n"},
+		{"\x1b[1;38;5;208mbold orange\x1b[m", "bold orange"},
+		{"\x1b[2K\rcleared", "\rcleared"},
+		{"\x1b]8;;http://example.com\x1b\\link\x1b]8;;\x1b\\", "link"},
+		{"\x1b]0;title\x07text", "text"},
+	}
+
+	for _, tt := range tbl {
+		t.Run(tt.out, func(t *testing.T) {
+			buf := bytes.Buffer{}
+			n, err := StripANSI(&buf).Write([]byte(tt.inp))
+			require.NoError(t, err)
+			assert.Equal(t, len(tt.inp), n)
+			assert.Equal(t, tt.out, buf.String())
+		})
+	}
+
+	term, file := bytes.Buffer{}, bytes.Buffer{}
+	l := New(Out(io.MultiWriter(&term, StripANSI(&file))),
+		Map(Mapper{MessageFunc: func(s string) string { return "\x1b[32m" + s + "\x1b[0m" }}))
+	l.Logf("INFO colored")
+	assert.Contains(t, term.String(), "INFO  \x1b[32mcolored\x1b[0m\n")
+	assert.Contains(t, file.String(), "INFO  colored\n")
+	assert.NotContains(t, file.String(), "\x1b")
+}
+
+func TestMultiWriter(t *testing.T) {
+	out, f := bytes.NewBuffer([]byte{}), bytes.NewBuffer([]byte{})
+	l := New(Out(MultiWriter(out, f)), Err(out), Format(`{{.Level}} {{.Message}}`))
+	l.Logf("ERROR failed")
+	assert.Equal(t, "ERROR failed\n", out.String(), "no duplicate for the err writer included in out")
+	assert.Equal(t, "ERROR failed\n", f.String())
+
+	other := bytes.NewBuffer([]byte{})
+	assert.True(t, isStreamsSame(MultiWriter(out, f), unwrappingWriter{out}))
+	assert.True(t, isStreamsSame(MultiWriter(out, f), MultiWriter(f, out)))
+	assert.False(t, isStreamsSame(out, MultiWriter(out, other)), "err has a target not covered by out")
+	assert.False(t, isStreamsSame(MultiWriter(f, other), out))
+	assert.False(t, isStreamsSame(out, MultiWriter()))
+	assert.False(t, isStreamsSame(io.MultiWriter(out, f), out), "io.MultiWriter hides its targets")
+}
@@ -0,0 +1,197 @@
+package lgr
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+)
+
+var (
+	formatsLock sync.RWMutex
+	regFormats  = map[string]string{} // registered formats, name -> template
+)
+
+// RegisterFormat registers named format, to be referenced by name in Format and FormatErr, i.e. Format("audit"),
+// or included by other templates, i.e. Format(`{{template "audit" .}} extra`). Registering the same name again
+// replaces the format for loggers made after the call. Lets organizations share standard layouts via a small package.
+func RegisterFormat(name, format string) error {
+	if name == "" || name == "lgr" {
+		return errors.New("lgr: invalid format name")
+	}
+	format = strings.ReplaceAll(format, "[{{.Level}}]", "{{.LevelBraces}}") // padding-aware braces
+	templ, err := newTemplate(format)
+	if err == nil {
+		err = templ.Execute(&bytes.Buffer{}, layout{})
+	}
+	if err != nil {
+		return fmt.Errorf("lgr: invalid format %q: %w", name, err)
+	}
+
+	formatsLock.Lock()
+	regFormats[name] = format
+	formatsLock.Unlock()
+	return nil
+}
+
+// registeredFormat returns template of registered format
+func registeredFormat(name string) (string, bool) {
+	formatsLock.RLock()
+	defer formatsLock.RUnlock()
+	f, ok := regFormats[name]
+	return f, ok
+}
+
+// withIncludedFormats appends registered formats referenced by quoted name to the format, including formats
+// referenced by the appended ones, so checks for fields in use see fields of the included formats
+func withIncludedFormats(format string) string {
+	if !strings.Contains(format, "template") {
+		return format
+	}
+	formatsLock.RLock()
+	defer formatsLock.RUnlock()
+	included := map[string]bool{}
+	for found := true; found; {
+		found = false
+		for name, f := range regFormats {
+			if !included[name] && strings.Contains(format, `"`+name+`"`) {
+				included[name], found = true, true
+				format += f
+			}
+		}
+	}
+	return format
+}
+
+// newTemplate parses format with registered formats available as named templates
+func newTemplate(format string) (*template.Template, error) {
+	templ := template.New("lgr").Funcs(templateFuncs)
+	formatsLock.RLock()
+	defer formatsLock.RUnlock()
+	for name, f := range regFormats {
+		if _, err := templ.New(name).Parse(f); err != nil {
+			return nil, err
+		}
+	}
+	return templ.Parse(format)
+}
+
+// templateFuncs available in Format templates
+var templateFuncs = template.FuncMap{
+	"json":        jsonString,
+	"jsonValue":   jsonValue,
+	"gcpSeverity": gcpSeverity,
+	"ghCommand":   ghCommand,
+	"ghData":      ghData,
+	"ghProp":      ghProp,
+	"ghFile":      ghFile,
+	"tap":         tapComment,
+	"logfmt":      logfmtValue,
+	"lower":       strings.ToLower,
+	"zapLevel":    zapLevel,
+	"epoch":       epochSeconds,
+}
+
+// jsonString makes quoted and escaped JSON string
+func jsonString(s string) string {
+	res, err := json.Marshal(s)
+	if err != nil { // can't happen for strings
+		return `""`
+	}
+	return string(res)
+}
+
+// gcpSeverity maps level to Google Cloud Logging severity
+func gcpSeverity(level string) string {
+	switch strings.TrimSpace(level) {
+	case "TRACE", "DEBUG":
+		return "DEBUG"
+	case "INFO":
+		return "INFO"
+	case "WARN":
+		return "WARNING"
+	case "ERROR":
+		return "ERROR"
+	case "PANIC":
+		return "ALERT"
+	case "FATAL":
+		return "CRITICAL"
+	}
+	return "DEFAULT"
+}
+
+// zapLevel maps level to zap level name, TRACE reported as debug as zap has no trace level
+func zapLevel(level string) string {
+	if level = strings.ToLower(strings.TrimSpace(level)); level == "trace" {
+		return "debug"
+	}
+	return level
+}
+
+// epochSeconds formats time as fractional seconds since epoch, i.e. 1515330154.123
+func epochSeconds(t time.Time) string {
+	res := strconv.FormatInt(t.Unix(), 10)
+	if ns := t.Nanosecond(); ns > 0 {
+		res += strings.TrimRight(fmt.Sprintf(".%09d", ns), "0")
+	}
+	return res
+}
+
+// ghCommand maps level to GitHub Actions workflow command, empty for levels without annotation
+func ghCommand(level string) string {
+	switch strings.TrimSpace(level) {
+	case "WARN":
+		return "warning"
+	case "ERROR", "PANIC", "FATAL":
+		return "error"
+	}
+	return ""
+}
+
+// ghData escapes data of GitHub Actions workflow command
+func ghData(s string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A").Replace(s)
+}
+
+// ghFile makes file path relative to GITHUB_WORKSPACE, the repository checkout, returns path as is if it's outside
+func ghFile(path string) string {
+	ws := os.Getenv("GITHUB_WORKSPACE")
+	if ws == "" || path == "" {
+		return path
+	}
+	rel, err := filepath.Rel(ws, path)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return path
+	}
+	return filepath.ToSlash(rel)
+}
+
+// ghProp escapes property value of GitHub Actions workflow command
+func ghProp(s string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A", ":", "%3A", ",", "%2C").Replace(s)
+}
+
+// tapComment prefixes continuation lines of multiline message with "# ", so the whole message stays TAP comment
+func tapComment(s string) string {
+	return strings.ReplaceAll(s, "\n", "\n# ")
+}
+
+// logfmtValue quotes and escapes value for logfmt if it's empty or has spaces, quotes, "=" or control characters
+func logfmtValue(s string) string {
+	if s == "" {
+		return `""`
+	}
+	for _, r := range s {
+		if r <= ' ' || r == '=' || r == '"' || r == 0x7f || !strconv.IsPrint(r) {
+			return strconv.Quote(s)
+		}
+	}
+	return s
+}
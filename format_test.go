@@ -0,0 +1,202 @@
+package lgr
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoggerFormatGCP(t *testing.T) {
+	rout, rerr := bytes.NewBuffer([]byte{}), bytes.NewBuffer([]byte{})
+	l := New(Out(rout), Err(rerr), Debug, Format(GCP))
+	l.now = func() time.Time { return time.Date(2018, 1, 7, 13, 2, 34, 123000000, time.UTC) }
+
+	l.Logf("WARN something \"quoted\"\n\tand multiline %d", 123)
+	rec := map[string]interface{}{}
+	require.NoError(t, json.Unmarshal(rout.Bytes(), &rec), rout.String())
+	assert.Equal(t, "WARNING", rec["severity"])
+	assert.Equal(t, "2018-01-07T13:02:34.123Z", rec["time"])
+	assert.Equal(t, "something \"quoted\"\n\tand multiline 123", rec["message"])
+
+	src, ok := rec["logging.googleapis.com/sourceLocation"].(map[string]interface{})
+	require.True(t, ok, rout.String())
+	assert.Equal(t, "lgr/format_test.go", src["file"])
+	assert.Equal(t, "18", src["line"])
+	assert.Equal(t, "lgr.TestLoggerFormatGCP", src["function"])
+
+	rout.Reset()
+	rerr.Reset()
+	l.Logf("ERROR failed")
+	assert.Equal(t, rout.String(), rerr.String())
+	assert.Contains(t, rout.String(), `{"severity":"ERROR","time":"2018-01-07T13:02:34.123Z","message":"failed",`)
+}
+
+func TestGCPSeverity(t *testing.T) {
+	tbl := map[string]string{"TRACE": "DEBUG", "DEBUG": "DEBUG", "INFO ": "INFO", "WARN ": "WARNING", "ERROR": "ERROR",
+		"PANIC": "ALERT", "FATAL": "CRITICAL", "": "DEFAULT"}
+	for lv, sev := range tbl {
+		assert.Equal(t, sev, gcpSeverity(lv), lv)
+	}
+}
+
+func TestLoggerFormatLevelRawAndNum(t *testing.T) {
+	rout, rerr := bytes.NewBuffer([]byte{}), bytes.NewBuffer([]byte{})
+	l := New(Out(rout), Err(rerr), Trace, Format(`{{.LevelRaw}}|{{.LevelNum}}|{{.Level}}|{{.Message}}`))
+	l.fatal = func() {}
+
+	for _, lv := range []string{"TRACE", "DEBUG", "INFO", "WARN", "ERROR", "FATAL", "PANIC"} {
+		l.Logf(lv + " msg")
+	}
+	l.Logf("no level")
+	assert.Equal(t, "TRACE|7|TRACE|msg\nDEBUG|7|DEBUG|msg\nINFO|6|INFO |msg\nWARN|4|WARN |msg\n"+
+		"ERROR|3|ERROR|msg\nFATAL|2|FATAL|msg\nPANIC|1|PANIC|msg\nINFO|6|INFO |no level\n", rout.String())
+}
+
+func TestLoggerFormatGitHubActions(t *testing.T) {
+	rout, rerr := bytes.NewBuffer([]byte{}), bytes.NewBuffer([]byte{})
+	l := New(Out(rout), Err(rerr), Format(GitHubActions))
+	l.now = func() time.Time { return time.Date(2018, 1, 7, 13, 2, 34, 0, time.UTC) }
+
+	t.Setenv("GITHUB_WORKSPACE", "")
+	l.Logf("INFO started")
+	l.Logf("WARN disk usage %d%%", 95)
+	l.Logf("ERROR failed\nsecond line")
+	assert.Regexp(t, `^2018/01/07 13:02:34 INFO  started\n`+
+		`::warning file=/.+/format_test.go,line=\d+::disk usage 95%25\n`+
+		`::error file=/.+/format_test.go,line=\d+::failed%0Asecond line\n$`, rout.String())
+}
+
+func TestCI(t *testing.T) {
+	t.Setenv("GITHUB_ACTIONS", "true")
+	l := New(CI)
+	assert.Equal(t, GitHubActions, l.format)
+
+	t.Setenv("GITHUB_ACTIONS", "")
+	l = New(CI)
+	assert.Equal(t, "", l.format)
+}
+
+func TestGhEscape(t *testing.T) {
+	assert.Equal(t, "a%25b%0D%0Ac:d,e", ghData("a%b\r\nc:d,e"))
+	assert.Equal(t, "a%25b%0D%0Ac%3Ad%2Ce", ghProp("a%b\r\nc:d,e"))
+}
+
+func TestLoggerFormatTAP(t *testing.T) {
+	rout, rerr := bytes.NewBuffer([]byte{}), bytes.NewBuffer([]byte{})
+	l := New(Out(rout), Err(rerr), Format(TAP))
+	l.now = func() time.Time { return time.Date(2018, 1, 7, 13, 2, 34, 0, time.UTC) }
+
+	l.Logf("INFO started")
+	l.Logf("ERROR failed\nsecond line")
+	assert.Equal(t, "# 2018/01/07 13:02:34 INFO  started\n# 2018/01/07 13:02:34 ERROR failed\n# second line\n", rout.String())
+}
+
+func TestLoggerFormatLogfmt(t *testing.T) {
+	rout, rerr := bytes.NewBuffer([]byte{}), bytes.NewBuffer([]byte{})
+	l := New(Out(rout), Err(rerr), Format(Logfmt))
+	l.now = func() time.Time { return time.Date(2018, 1, 7, 13, 2, 34, 123000000, time.UTC) }
+
+	l.Logf("WARN something \"quoted\" a=b\n\tmultiline %d", 123)
+	assert.Equal(t, `time=2018-01-07T13:02:34.123Z level=warn caller=lgr/format_test.go:103 func=lgr.TestLoggerFormatLogfmt `+
+		`msg="something \"quoted\" a=b\n\tmultiline 123"`+"\n", rout.String())
+
+	rout.Reset()
+	l.Logf("INFO single-word")
+	assert.Contains(t, rout.String(), " level=info ")
+	assert.Regexp(t, ` msg=single-word\n$`, rout.String())
+}
+
+func TestLogfmtValue(t *testing.T) {
+	tbl := map[string]string{"": `""`, "plain": "plain", "a b": `"a b"`, "k=v": `"k=v"`, `say "hi"`: `"say \"hi\""`,
+		"tab\tand\nnl": `"tab\tand\nnl"`, "привет": "привет", "bell\a": `"bell\a"`, "path/to:12": "path/to:12"}
+	for inp, res := range tbl {
+		assert.Equal(t, res, logfmtValue(inp), inp)
+	}
+}
+
+func TestRegisterFormat(t *testing.T) {
+	defer func() {
+		formatsLock.Lock()
+		delete(regFormats, "test-audit")
+		formatsLock.Unlock()
+	}()
+
+	require.NoError(t, RegisterFormat("test-audit", `AUDIT [{{.Level}}] {{.Message}}`))
+	assert.Error(t, RegisterFormat("", `{{.Message}}`))
+	assert.Error(t, RegisterFormat("test-bad", `{{.Message`))
+	assert.Error(t, RegisterFormat("test-bad", `{{.NoSuchField}}`))
+	assert.Error(t, RegisterFormat("test-bad", `{{template "test-bad" .}}`), "self reference")
+
+	buf := bytes.Buffer{}
+	l := New(Out(&buf), Format("test-audit"))
+	l.Logf("INFO message")
+	assert.Equal(t, "AUDIT [INFO]  message\n", buf.String())
+
+	buf.Reset()
+	l = New(Out(&buf), Format(`{{template "test-audit" .}} #{{.LevelNum}}`))
+	l.Logf("WARN message")
+	assert.Equal(t, "AUDIT [WARN]  message #4\n", buf.String())
+
+	buf.Reset()
+	l = New(Out(&buf), Format("no-such-format"))
+	l.Logf("INFO message")
+	assert.Equal(t, "no-such-format\n", buf.String(), "not registered name is a template")
+}
+
+func TestLoggerFormatZapZerolog(t *testing.T) {
+	tbl := []struct {
+		format string
+		res    string
+	}{
+		{Zap, `{"level":"debug","ts":1515330154.123,"caller":"lgr/format_test.go:164","msg":"message \"q\"","user":"joe","id":42}`},
+		{Zerolog, `{"level":"trace","time":"2018-01-07T13:02:34Z","caller":"lgr/format_test.go:164","message":"message \"q\"",` +
+			`"user":"joe","id":42}`},
+	}
+
+	for _, tt := range tbl {
+		buf := bytes.Buffer{}
+		l := New(Out(&buf), Trace, Format(tt.format))
+		l.now = func() time.Time { return time.Date(2018, 1, 7, 13, 2, 34, 123000000, time.UTC) }
+		l.WithFields(map[string]interface{}{"user": "joe"}).With("id", 42).Logf(`TRACE message "q"`)
+		assert.Equal(t, tt.res+"\n", buf.String())
+		assert.True(t, json.Valid(buf.Bytes()))
+	}
+
+	assert.Equal(t, "info", zapLevel("INFO "))
+	assert.Equal(t, "fatal", zapLevel("FATAL"))
+	assert.Equal(t, "1515330154", epochSeconds(time.Unix(1515330154, 0)))
+	assert.Equal(t, "1515330154.000000001", epochSeconds(time.Unix(1515330154, 1)))
+}
+
+func TestGhFile(t *testing.T) {
+	t.Setenv("GITHUB_WORKSPACE", "/home/runner/work/app/app")
+	assert.Equal(t, "cmd/app/main.go", ghFile("/home/runner/work/app/app/cmd/app/main.go"))
+	assert.Equal(t, "/home/runner/go/pkg/mod/lib/x.go", ghFile("/home/runner/go/pkg/mod/lib/x.go"), "outside workspace")
+	assert.Equal(t, "", ghFile(""))
+
+	t.Setenv("GITHUB_WORKSPACE", "")
+	assert.Equal(t, "/src/app/main.go", ghFile("/src/app/main.go"))
+}
+
+func TestLoggerRegisteredFormatWithFieldsAndTags(t *testing.T) {
+	defer func() {
+		formatsLock.Lock()
+		delete(regFormats, "test-fields")
+		formatsLock.Unlock()
+	}()
+	require.NoError(t, RegisterFormat("test-fields", `{{.Level}} {{.Message}} [{{.Fields}}] {{.Tags}}`))
+
+	buf := bytes.Buffer{}
+	l := New(Out(&buf), Format(`{{template "test-fields" .}} extra`))
+	l.With("user", "joe").Tagged("db").Logf("INFO hi")
+	assert.Equal(t, "INFO  hi [user=joe] #db extra\n", buf.String(), "fields and tags placed once by included format")
+
+	buf.Reset()
+	l = New(Out(&buf), Format(`{{.Level}} {{.Message}} extra`))
+	l.With("user", "joe").Tagged("db").Logf("INFO hi")
+	assert.Equal(t, "INFO  #db hi user=joe extra\n", buf.String(), "not included format doesn't count")
+}
@@ -0,0 +1,114 @@
+package lgr
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"runtime"
+	"sort"
+	"strings"
+)
+
+// TraceFn logs entry to the calling function at TRACE level, i.e. "-> pkg.Func", and returns func logging the exit
+// with duration, i.e. "<- pkg.Func (12ms)". Designed to be used as defer l.TraceFn()(). Does nothing without Trace.
+func (l *Logger) TraceFn() func() {
+	if !l.enabled("TRACE") {
+		return func() {}
+	}
+
+	fn := "unknown"
+	if pc, _, _, ok := runtime.Caller(1); ok {
+		if f := runtime.FuncForPC(pc); f != nil {
+			elems := strings.Split(f.Name(), "/")
+			fn = elems[len(elems)-1]
+		}
+	}
+
+	st := l.now()
+	l.logf("TRACE -> %s", fn)
+	return func() {
+		l.logf("TRACE <- %s (%v)", fn, l.now().Sub(st))
+	}
+}
+
+// Check logs error with context message at ERROR level, i.e. "ERROR context, error text", if err is not nil.
+// Returns true if err is not nil, i.e. if l.Check(err, "can't open file") { return }
+func (l *Logger) Check(err error, msg string) bool {
+	if err == nil {
+		return false
+	}
+	l.logf("ERROR %s, %v", msg, err)
+	return true
+}
+
+// Must logs error at FATAL level and exits, if err is not nil
+func (l *Logger) Must(err error) {
+	if err == nil {
+		return
+	}
+	l.logf("FATAL %v", err)
+}
+
+// VerifyCallerDepth checks if caller depth of the logger, i.e. set with CallerDepth for logging wrappers, resolves
+// to the real caller. Should be called the same way as logging methods, i.e. from the wrapper. Logs DEBUG record
+// with the resolved caller if depth is correct, WARN record and returns error if the caller is unknown,
+// i.e. depth is too large, or it's inside lgr package, i.e. depth is too small for adaptors.
+func VerifyCallerDepth(l *Logger) error {
+	var frame runtime.Frame
+	pcs := make([]uintptr, 1)
+	if runtime.Callers(l.callerDepth+2, pcs) == 1 { // skip runtime.Callers and VerifyCallerDepth
+		frame, _ = runtime.CallersFrames(pcs).Next()
+	}
+
+	var err error
+	switch {
+	case frame.Function == "" || strings.HasPrefix(frame.Function, "runtime."):
+		err = fmt.Errorf("lgr: caller depth %d is too large, caller not found", l.callerDepth)
+	case strings.HasPrefix(frame.Function, lgrPkgPath+".") && !strings.HasSuffix(frame.File, "_test.go"):
+		err = fmt.Errorf("lgr: caller depth %d resolved to %s inside lgr, increase the depth", l.callerDepth, frame.Function)
+	}
+	if err != nil {
+		l.logf("WARN %v", err)
+		return err
+	}
+	l.logf("DEBUG lgr: caller depth %d resolved to %s:%d %s", l.callerDepth, frame.File, frame.Line, frame.Function)
+	return nil
+}
+
+// SelfTest writes INFO probe record to each writer of the logger, i.e. out, err and writers set with TagOut,
+// and returns joined errors of failed writes. Allows to catch misconfigured output at startup.
+func (l *Logger) SelfTest() error {
+	type dest struct {
+		name string
+		w    io.Writer
+	}
+	dests := []dest{{name: "out", w: l.stdout}}
+	if !l.sameStream {
+		dests = append(dests, dest{name: "err", w: l.stderr})
+	}
+	tags := make([]string, 0, len(l.tagRoutes))
+	for tag := range l.tagRoutes {
+		tags = append(tags, tag)
+	}
+	sort.Strings(tags)
+	for _, tag := range tags {
+		dests = append(dests, dest{name: "tag " + tag, w: l.tagRoutes[tag].out})
+	}
+
+	elems := layout{DT: l.now(), Level: l.formatLevel("INFO"), LevelRaw: "INFO", LevelNum: levelNum("INFO"),
+		LevelBraces: l.formatLevelBraces("INFO"), Message: "lgr: self-test probe"}
+	data := l.hideSecrets(l.render(elems))
+
+	var errs []error
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	for _, d := range dests {
+		if _, err := d.w.Write(data); err != nil {
+			errs = append(errs, fmt.Errorf("lgr: self-test of %s writer failed: %w", d.name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+var lgrPkgPath = reflect.TypeOf(Logger{}).PkgPath()
@@ -0,0 +1,117 @@
+package lgr
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLogger_TraceFn(t *testing.T) {
+	rout := bytes.NewBuffer([]byte{})
+	l := New(Out(rout), Trace, Format(`{{.Level}} {{.CallerFunc}}:{{.CallerLine}} {{.Message}}`))
+	ts := time.Date(2018, 1, 7, 13, 2, 34, 0, time.Local)
+	l.now = func() time.Time { return ts }
+
+	func() {
+		defer l.TraceFn()()
+		ts = ts.Add(12 * time.Millisecond)
+		l.Logf("INFO inside")
+	}()
+	assert.Equal(t, "TRACE lgr.TestLogger_TraceFn.func2:19 -> lgr.TestLogger_TraceFn.func2\n"+
+		"INFO  lgr.TestLogger_TraceFn.func2:21 inside\n"+
+		"TRACE lgr.TestLogger_TraceFn.func2:22 <- lgr.TestLogger_TraceFn.func2 (12ms)\n", rout.String())
+
+	rout.Reset()
+	l = New(Out(rout), Debug)
+	func() {
+		defer l.TraceFn()()
+	}()
+	assert.Equal(t, "", rout.String(), "no trace")
+}
+
+func BenchmarkTraceFnOff(b *testing.B) {
+	l := New(Out(bytes.NewBuffer([]byte{})))
+	for n := 0; n < b.N; n++ {
+		l.TraceFn()()
+	}
+}
+
+func TestLogger_Check(t *testing.T) {
+	rout, rerr := bytes.NewBuffer([]byte{}), bytes.NewBuffer([]byte{})
+	l := New(Out(rout), Err(rerr), CallerFunc, Format(`{{.Level}} {{.CallerFunc}} {{.Message}}`))
+
+	assert.False(t, l.Check(nil, "can't open file"))
+	assert.Equal(t, "", rout.String())
+
+	assert.True(t, l.Check(errors.New("no such file"), "can't open file"))
+	assert.Equal(t, "ERROR lgr.TestLogger_Check can't open file, no such file\n", rout.String())
+	assert.Equal(t, "ERROR lgr.TestLogger_Check can't open file, no such file\n", rerr.String())
+}
+
+func TestLogger_Must(t *testing.T) {
+	fatalCalls := 0
+	rout, rerr := bytes.NewBuffer([]byte{}), bytes.NewBuffer([]byte{})
+	l := New(Out(rout), Err(rerr), CallerFunc, Format(`{{.Level}} {{.CallerFunc}} {{.Message}}`))
+	l.fatal = func() { fatalCalls++ }
+
+	l.Must(nil)
+	assert.Equal(t, 0, fatalCalls)
+	assert.Equal(t, "", rout.String())
+
+	l.Must(errors.New("no such file"))
+	assert.Equal(t, 1, fatalCalls)
+	assert.Equal(t, "FATAL lgr.TestLogger_Must no such file\n", rout.String())
+}
+
+func TestVerifyCallerDepth(t *testing.T) {
+	rout := bytes.NewBuffer([]byte{})
+	l := New(Out(rout), Err(rout), Debug, Format(`{{.Level}} {{.CallerFunc}} {{.Message}}`))
+	assert.NoError(t, VerifyCallerDepth(l))
+	assert.Contains(t, rout.String(), "DEBUG lgr.TestVerifyCallerDepth lgr: caller depth 0 resolved to ")
+	assert.Contains(t, rout.String(), "helpers_test.go:")
+
+	wrapper := func(l *Logger) error { return VerifyCallerDepth(l) }
+	rout.Reset()
+	l = New(Out(rout), Err(rout), Debug, CallerDepth(1), Format(`{{.Level}} {{.CallerFunc}} {{.Message}}`))
+	assert.NoError(t, wrapper(l))
+	assert.Contains(t, rout.String(), "DEBUG lgr.TestVerifyCallerDepth lgr: caller depth 1 resolved to ")
+
+	rout.Reset()
+	l = New(Out(rout), Err(rout), CallerDepth(100), Format(`{{.Level}} {{.Message}}`))
+	err := VerifyCallerDepth(l)
+	assert.EqualError(t, err, "lgr: caller depth 100 is too large, caller not found")
+	assert.Equal(t, "WARN  lgr: caller depth 100 is too large, caller not found\n", rout.String())
+}
+
+func TestVerifyCallerDepthInsideLgr(t *testing.T) {
+	rout := bytes.NewBuffer([]byte{})
+	l := New(Out(rout), Err(rout), CallerDepth(-1), Format(`{{.Level}} {{.Message}}`))
+	err := VerifyCallerDepth(l)
+	assert.EqualError(t, err, "lgr: caller depth -1 resolved to github.com/go-pkgz/lgr.VerifyCallerDepth inside lgr, "+
+		"increase the depth")
+	assert.Contains(t, rout.String(), "WARN  lgr: caller depth -1 resolved to")
+}
+
+func TestLogger_SelfTest(t *testing.T) {
+	rout, rerr, rtag := bytes.NewBuffer([]byte{}), bytes.NewBuffer([]byte{}), bytes.NewBuffer([]byte{})
+	l := New(Out(rout), Err(rerr), TagOut("db", rtag), Format(`{{.Level}} {{.Message}}`))
+	assert.NoError(t, l.SelfTest())
+	assert.Equal(t, "INFO  lgr: self-test probe\n", rout.String())
+	assert.Equal(t, "INFO  lgr: self-test probe\n", rerr.String())
+	assert.Equal(t, "INFO  lgr: self-test probe\n", rtag.String())
+
+	rout.Reset()
+	l = New(Out(rout), Err(rout), TagOut("db", failingWriter{}), TagOut("auth", failingWriter{}))
+	err := l.SelfTest()
+	assert.EqualError(t, err, "lgr: self-test of tag auth writer failed: write failed\n"+
+		"lgr: self-test of tag db writer failed: write failed")
+	assert.Contains(t, rout.String(), " INFO  lgr: self-test probe\n")
+	assert.Equal(t, len(rout.String()), len("2018/01/07 13:02:34 INFO  lgr: self-test probe\n"), "same stream written once")
+}
+
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) { return 0, errors.New("write failed") }
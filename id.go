@@ -0,0 +1,71 @@
+package lgr
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"os"
+	"sync/atomic"
+	"time"
+)
+
+// xid-style layout: 4 bytes of unix time, 3 bytes of machine id, 2 bytes of pid and 3 bytes of counter,
+// encoded with sortable base32hex alphabet
+const idAlphabet = "0123456789abcdefghijklmnopqrstuv"
+
+var (
+	idMachine = machineID()
+	idPid     = uint16(os.Getpid()) //nolint:gosec // truncation is fine, only 2 bytes used
+	idCounter = randUint32()
+)
+
+// NewID makes a globally unique, k-sortable 20 chars id, i.e. "cjk2uq4vk9r4ok0p3g2g".
+// Ids roughly time-ordered, with seconds resolution; order within a second breaks when the counter wraps.
+// Suitable for request/correlation ids.
+func NewID() string {
+	var raw [12]byte
+	binary.BigEndian.PutUint32(raw[0:], uint32(time.Now().Unix())) //nolint:gosec // good till 2106
+	copy(raw[4:7], idMachine[:])
+	binary.BigEndian.PutUint16(raw[7:], idPid)
+	cnt := atomic.AddUint32(&idCounter, 1)
+	raw[9], raw[10], raw[11] = byte(cnt>>16), byte(cnt>>8), byte(cnt)
+	return encodeID(raw)
+}
+
+// encodeID encodes 12 bytes (96 bits) to 20 chars with 5 bits per char, the last char gets 1 bit padded
+func encodeID(raw [12]byte) string {
+	var dst [20]byte
+	var acc uint64
+	bits, n := 0, 0
+	for _, b := range raw {
+		acc = acc<<8 | uint64(b)
+		bits += 8
+		for bits >= 5 {
+			bits -= 5
+			dst[n] = idAlphabet[(acc>>uint(bits))&0x1f]
+			n++
+		}
+	}
+	if bits > 0 {
+		dst[n] = idAlphabet[(acc<<uint(5-bits))&0x1f]
+	}
+	return string(dst[:])
+}
+
+// machineID returns 3 bytes of hostname hash, random bytes if hostname can't be detected
+func machineID() (res [3]byte) {
+	hostname, err := os.Hostname()
+	if err != nil || hostname == "" {
+		_, _ = rand.Read(res[:])
+		return res
+	}
+	h := sha256.Sum256([]byte(hostname))
+	copy(res[:], h[:3])
+	return res
+}
+
+func randUint32() uint32 {
+	var b [4]byte
+	_, _ = rand.Read(b[:])
+	return binary.BigEndian.Uint32(b[:])
+}
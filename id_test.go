@@ -0,0 +1,72 @@
+package lgr
+
+import (
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewID(t *testing.T) {
+	ids := make([]string, 0, 1000)
+	for i := 0; i < 1000; i++ {
+		id := NewID()
+		assert.Len(t, id, 20)
+		ids = append(ids, id)
+	}
+	// counter can overflow to 0 in the middle, so check uniqueness rather than order for the whole set
+	seen := map[string]bool{}
+	for _, id := range ids {
+		assert.False(t, seen[id], "duplicate id %s", id)
+		seen[id] = true
+	}
+	start, end := idTime(ids[0]), idTime(ids[999])
+	assert.LessOrEqual(t, start, end)
+	assert.LessOrEqual(t, end-start, uint32(1), "generated within a second or two")
+	assert.InDelta(t, time.Now().Unix(), int64(start), 5, "time encoded")
+}
+
+// idTime decodes unix time from the first 7 chars of id, 35 bits with 32 bits of time
+func idTime(id string) uint32 {
+	var acc uint64
+	for _, c := range id[:7] {
+		acc = acc<<5 | uint64(strings.IndexRune(idAlphabet, c))
+	}
+	return uint32(acc >> 3)
+}
+
+func TestNewIDConcurrent(t *testing.T) {
+	var mu sync.Mutex
+	seen := map[string]bool{}
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 1000; j++ {
+				id := NewID()
+				mu.Lock()
+				seen[id] = true
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, 10000, len(seen))
+}
+
+func TestEncodeID(t *testing.T) {
+	assert.Equal(t, "00000000000000000000", encodeID([12]byte{}))
+	assert.Equal(t, "vvvvvvvvvvvvvvvvvvvg", encodeID([12]byte{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff}))
+
+	ids := []string{
+		encodeID([12]byte{0, 0, 0, 1}),
+		encodeID([12]byte{0, 0, 0, 2}),
+		encodeID([12]byte{0, 0, 1, 0}),
+		encodeID([12]byte{1, 0, 0, 0}),
+	}
+	assert.True(t, sort.StringsAreSorted(ids), "sortable encoding")
+}
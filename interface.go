@@ -1,6 +1,8 @@
 package lgr
 
 import (
+	"fmt"
+	"io"
 	stdlog "log"
 )
 
@@ -11,15 +13,131 @@ type L interface {
 	Logf(format string, args ...interface{})
 }
 
+// LeveledL defines interface with leveled methods, for consumers preferring explicit levels over prefixes
+type LeveledL interface {
+	Debugf(format string, args ...interface{})
+	Infof(format string, args ...interface{})
+	Warnf(format string, args ...interface{})
+	Errorf(format string, args ...interface{})
+}
+
+// ToLeveled upgrades L to LeveledL. Returns l itself if it implements LeveledL already (as *Logger does),
+// otherwise wraps it adding level prefixes to messages.
+func ToLeveled(l L) LeveledL {
+	if ll, ok := l.(LeveledL); ok {
+		return ll
+	}
+	return leveled{l}
+}
+
+// FromLeveled downgrades LeveledL to L. Returns ll itself if it implements L already, otherwise wraps it
+// dispatching messages to leveled methods by level prefix. TRACE sent to Debugf, PANIC and FATAL to Errorf.
+func FromLeveled(ll LeveledL) L {
+	if l, ok := ll.(L); ok {
+		return l
+	}
+	return Func(func(format string, args ...interface{}) {
+		lv, msg := ParseLevel(format)
+		switch lv {
+		case "TRACE", "DEBUG":
+			ll.Debugf(msg, args...)
+		case "WARN":
+			ll.Warnf(msg, args...)
+		case "ERROR", "PANIC", "FATAL":
+			ll.Errorf(msg, args...)
+		default:
+			ll.Infof(msg, args...)
+		}
+	})
+}
+
+// StructuredL defines interface with key-value pairs methods, for consumers logging structured records
+type StructuredL interface {
+	Logw(level, msg string, kvs ...interface{})
+	Debugw(msg string, kvs ...interface{})
+	Infow(msg string, kvs ...interface{})
+	Warnw(msg string, kvs ...interface{})
+	Errorw(msg string, kvs ...interface{})
+}
+
+// ToStructured upgrades L to StructuredL. Returns l itself if it implements StructuredL already (as *Logger does),
+// otherwise wraps it adding level prefix to messages and pairs as " key=value" after the message.
+func ToStructured(l L) StructuredL {
+	if sl, ok := l.(StructuredL); ok {
+		return sl
+	}
+	return structured{l}
+}
+
+// FromStructured downgrades StructuredL to L. Returns sl itself if it implements L already, otherwise wraps it
+// sending formatted messages without pairs to Logw with the level parsed from prefix. Format without args sent as is.
+func FromStructured(sl StructuredL) L {
+	if l, ok := sl.(L); ok {
+		return l
+	}
+	return LevelFunc(func(level, format string, args ...interface{}) {
+		if len(args) == 0 {
+			sl.Logw(level, format)
+			return
+		}
+		sl.Logw(level, fmt.Sprintf(format, args...))
+	})
+}
+
+// leveled wraps L with leveled methods
+type leveled struct {
+	L
+}
+
+func (l leveled) Debugf(format string, args ...interface{}) { l.Logf("DEBUG "+format, args...) }
+func (l leveled) Infof(format string, args ...interface{})  { l.Logf("INFO "+format, args...) }
+func (l leveled) Warnf(format string, args ...interface{})  { l.Logf("WARN "+format, args...) }
+func (l leveled) Errorf(format string, args ...interface{}) { l.Logf("ERROR "+format, args...) }
+
+// structured wraps L with key-value pairs methods
+type structured struct {
+	L
+}
+
+func (s structured) Logw(level, msg string, kvs ...interface{}) {
+	if len(kvs) > 0 {
+		msg += " " + Fields(nil).pairs(kvs).String()
+	}
+	s.Logf(pairsLevel(level)+" %s", msg)
+}
+
+func (s structured) Debugw(msg string, kvs ...interface{}) { s.Logw("DEBUG", msg, kvs...) }
+func (s structured) Infow(msg string, kvs ...interface{})  { s.Logw("INFO", msg, kvs...) }
+func (s structured) Warnw(msg string, kvs ...interface{})  { s.Logw("WARN", msg, kvs...) }
+func (s structured) Errorw(msg string, kvs ...interface{}) { s.Logw("ERROR", msg, kvs...) }
+
 // Func type is an adapter to allow the use of ordinary functions as Logger.
 type Func func(format string, args ...interface{})
 
 // Logf calls f(format, args...)
 func (f Func) Logf(format string, args ...interface{}) { f(format, args...) }
 
+// LevelFunc type is an adapter to allow the use of ordinary functions receiving parsed level as Logger.
+// Level parsed from the format prefix, INFO if not set, and the format passed without it.
+type LevelFunc func(level, format string, args ...interface{})
+
+// Logf parses level and calls f(level, format, args...)
+func (f LevelFunc) Logf(format string, args ...interface{}) {
+	lv, msg := ParseLevel(format)
+	f(lv, msg, args...)
+}
+
 // NoOp logger
 var NoOp = Func(func(format string, args ...interface{}) {}) //nolint:revive
 
+// NoOpLogger is *Logger dropping all messages, for cases where *Logger (not just L) is required.
+// Supports the whole Logger API, FATAL and PANIC messages don't exit.
+var NoOpLogger = func() *Logger {
+	res := New(Out(io.Discard), Err(io.Discard))
+	res.noop = true
+	return res
+}()
+
 // Std logger sends to std default logger directly
 var Std = Func(func(format string, args ...interface{}) { stdlog.Printf(format, args...) })
 
@@ -36,7 +154,7 @@ func Print(line string) {
 // Fatalf simplifies replacement of std logger
 func Fatalf(format string, args ...interface{}) {
 	def.logf(format, args...)
-	def.fatal()
+	def.exit()
 }
 
 // Setup default logger with options
@@ -101,3 +101,103 @@ func TestDefaultFatal(t *testing.T) {
 	assert.Equal(t, "2018/01/07 13:02:34 ERROR something 123 xyz\n", buff.String())
 	assert.Equal(t, 1, fatal)
 }
+
+func TestNoOpLogger(t *testing.T) {
+	fatalCalls := 0
+	l := NoOpLogger.Tagged("db")
+	l.fatal = func() { fatalCalls++ }
+	l.Logf("INFO something")
+	l.Logf("FATAL something")
+	l.Must(errors.New("some error"))
+	assert.True(t, l.Check(errors.New("some error"), "context"))
+	defer l.TraceFn()()
+	defer l.Indent()()
+	l.Status("progress")
+	l.StatusDone()
+	_, err := l.Writer("WARN").Write([]byte("something"))
+	assert.NoError(t, err)
+	l.Std().Fatal("something")
+	l.Std().Fatalf("something %d", 1)
+	l.Std().Fatalln("something")
+	assert.Equal(t, 0, fatalCalls)
+}
+
+func TestLeveled(t *testing.T) {
+	rout, rerr := bytes.NewBuffer([]byte{}), bytes.NewBuffer([]byte{})
+	l := New(Out(rout), Err(rerr), Debug, CallerFunc, Format(`{{.Level}} {{.CallerFunc}} {{.Message}}`))
+	ll := ToLeveled(l)
+	assert.True(t, ll == LeveledL(l), "*Logger is LeveledL")
+	ll.Debugf("debug %d", 1)
+	ll.Infof("info %d", 2)
+	ll.Warnf("warn %d", 3)
+	ll.Errorf("error %d", 4)
+	assert.Equal(t, "DEBUG lgr.TestLeveled debug 1\nINFO  lgr.TestLeveled info 2\nWARN  lgr.TestLeveled warn 3\n"+
+		"ERROR lgr.TestLeveled error 4\n", rout.String())
+	assert.Equal(t, "ERROR lgr.TestLeveled error 4\n", rerr.String())
+	assert.True(t, FromLeveled(ll) == L(l), "*Logger is L")
+
+	var lines []string
+	fl := Func(func(format string, args ...interface{}) { lines = append(lines, fmt.Sprintf(format, args...)) })
+	ll = ToLeveled(fl)
+	ll.Debugf("debug %d", 1)
+	ll.Errorf("error %d", 4)
+	assert.Equal(t, []string{"DEBUG debug 1", "ERROR error 4"}, lines)
+
+	back := FromLeveled(struct{ LeveledL }{ll}) // hide L implementation
+	lines = nil
+	back.Logf("[TRACE] trace %d", 0)
+	back.Logf("something %d", 2)
+	back.Logf("WARN warn %d", 3)
+	back.Logf("PANIC panic %d", 5)
+	assert.Equal(t, []string{"DEBUG trace 0", "INFO something 2", "WARN warn 3", "ERROR panic 5"}, lines)
+}
+
+func TestLevelFunc(t *testing.T) {
+	var lines []string
+	var l L = LevelFunc(func(level, format string, args ...interface{}) {
+		lines = append(lines, level+"|"+fmt.Sprintf(format, args...))
+	})
+	l.Logf("DEBUG something %d", 1)
+	l.Logf("[WARN] something %d", 2)
+	l.Logf("something %d", 3)
+	assert.Equal(t, []string{"DEBUG|something 1", "WARN|something 2", "INFO|something 3"}, lines)
+}
+
+func TestStructured(t *testing.T) {
+	rout, rerr := bytes.NewBuffer([]byte{}), bytes.NewBuffer([]byte{})
+	l := New(Out(rout), Err(rerr), Debug, Format(`{{.Level}} {{.Message}}`))
+	sl := ToStructured(l)
+	assert.True(t, sl == StructuredL(l), "*Logger is StructuredL")
+	sl.Debugw("debug", "k", 1)
+	sl.Errorw("error", "k", 4)
+	assert.Equal(t, "DEBUG debug k=1\nERROR error k=4\n", rout.String())
+	assert.True(t, FromStructured(sl) == L(l), "*Logger is L")
+
+	var lines []string
+	fl := Func(func(format string, args ...interface{}) { lines = append(lines, fmt.Sprintf(format, args...)) })
+	sl = ToStructured(fl)
+	sl.Debugw("debug 100%", "k", 1, "path", "/a b")
+	sl.Infow("info")
+	sl.Warnw("warn", "k")
+	sl.Logw("bad", "unknown level", 5, true)
+	assert.Equal(t, []string{`DEBUG debug 100% k=1 path="/a b"`, "INFO info", "WARN warn !BADKEY=k",
+		"INFO unknown level 5=true"}, lines)
+
+	var recs []string
+	back := FromStructured(structuredRec{rec: &recs}) // no L implementation
+	back.Logf("[TRACE] trace %d", 0)
+	back.Logf("something %d", 2)
+	back.Logf("WARN warn %d", 3)
+	back.Logf("INFO 100% done")
+	assert.Equal(t, []string{"TRACE|trace 0", "INFO|something 2", "WARN|warn 3", "INFO|100% done"}, recs)
+}
+
+// structuredRec records Logw calls as level|msg
+type structuredRec struct {
+	StructuredL
+	rec *[]string
+}
+
+func (s structuredRec) Logw(level, msg string, _ ...interface{}) {
+	*s.rec = append(*s.rec, level+"|"+msg)
+}
@@ -0,0 +1,97 @@
+package lgr
+
+import (
+	"fmt"
+	"strings"
+	"sync/atomic"
+)
+
+// SetLevel sets the lowest reported level, i.e. SetLevel("DEBUG") to turn debug on or SetLevel("WARN") to drop INFO,
+// without re-creating the logger. Shared with child loggers and safe to call concurrently with logging.
+// FATAL and PANIC are always reported. Returns error for unknown level.
+func (l *Logger) SetLevel(level string) error {
+	idx := levelIndex(strings.ToUpper(strings.TrimSpace(level)))
+	if idx < 0 {
+		return fmt.Errorf("lgr: unknown level %q", level)
+	}
+	atomic.StoreInt32(l.level, int32(idx))
+	return nil
+}
+
+// GetLevel returns the lowest reported level, INFO by default or set by Debug, Trace and MinLevel options
+func (l *Logger) GetLevel() string {
+	return levels[atomic.LoadInt32(l.level)]
+}
+
+// SetLevel sets the lowest reported level of the default logger, see (*Logger).SetLevel
+func SetLevel(level string) error { return def.SetLevel(level) }
+
+// GetLevel returns the lowest reported level of the default logger
+func GetLevel() string { return def.GetLevel() }
+
+// enabled checks if records of the level reported, by the caller of any package if PackageLevel set
+func (l *Logger) enabled(lv string) bool {
+	if lv == "FATAL" || lv == "PANIC" {
+		return true
+	}
+	idx := levelIndex(lv)
+	if idx >= int(atomic.LoadInt32(l.level)) {
+		return true
+	}
+	for _, pl := range l.pkgLevels {
+		if idx >= pl {
+			return true
+		}
+	}
+	return false
+}
+
+// pkgEnabled checks if records of the level reported for the caller's package path. The level of PackageLevel key
+// matching whole elements of the path and ending deepest in it used, the longest key for equal depth.
+// Logger's level used if no key matched.
+func (l *Logger) pkgEnabled(lv, pkgPath string) bool {
+	if lv == "FATAL" || lv == "PANIC" {
+		return true
+	}
+	minLevel, matchEnd, matchLen := int(atomic.LoadInt32(l.level)), -1, 0
+	path := "/" + pkgPath + "/"
+	for pkg, pl := range l.pkgLevels {
+		idx := strings.LastIndex(path, "/"+pkg+"/")
+		if idx < 0 {
+			continue
+		}
+		if end := idx + len(pkg); end > matchEnd || (end == matchEnd && len(pkg) > matchLen) {
+			minLevel, matchEnd, matchLen = pl, end, len(pkg)
+		}
+	}
+	return levelIndex(lv) >= minLevel
+}
+
+// lowerLevel sets the lowest reported level if it's lower than the current one, unknown level ignored
+func (l *Logger) lowerLevel(lv string) {
+	if idx := int32(levelIndex(lv)); idx >= 0 && idx < atomic.LoadInt32(l.level) {
+		atomic.StoreInt32(l.level, idx)
+	}
+}
+
+// levelUnset is stored level of the logger before options applied, above any level
+var levelUnset = int32(len(levels))
+
+// newLevel makes level storage for the level, unknown level stored as levelUnset
+func newLevel(lv string) *int32 {
+	res := levelUnset
+	if idx := levelIndex(lv); idx >= 0 {
+		res = int32(idx)
+	}
+	return &res
+}
+
+// levelIndex returns index of the level in levels, from TRACE to FATAL, or -1 for unknown level
+func levelIndex(lv string) int {
+	for i, v := range levels {
+		if v == lv {
+			return i
+		}
+	}
+	return -1
+}
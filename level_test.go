@@ -0,0 +1,137 @@
+package lgr
+
+import (
+	"bytes"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoggerSetLevel(t *testing.T) {
+	buf := bytes.Buffer{}
+	l := New(Out(&buf), Err(&bytes.Buffer{}), Format(`{{.Level}} {{.Message}}`))
+	l.fatal = func() {}
+	child := l.Tagged("db")
+	assert.Equal(t, "INFO", l.GetLevel())
+
+	l.Logf("DEBUG filtered")
+	require.NoError(t, l.SetLevel("debug"))
+	assert.Equal(t, "DEBUG", child.GetLevel(), "shared with child")
+	l.Logf("DEBUG reported")
+	child.Logf("TRACE filtered")
+
+	require.NoError(t, child.SetLevel(" WARN "))
+	l.Logf("INFO filtered")
+	l.Logf("WARN reported")
+	l.Logf("ERROR reported")
+
+	require.NoError(t, l.SetLevel("FATAL"))
+	l.Logf("ERROR filtered")
+	l.Logf("FATAL always reported")
+
+	assert.Error(t, l.SetLevel("VERBOSE"))
+	assert.Equal(t, "FATAL", l.GetLevel())
+	assert.Equal(t, "DEBUG reported\nWARN  reported\nERROR reported\nFATAL always reported\n", buf.String())
+}
+
+func TestLoggerLevelOptions(t *testing.T) {
+	assert.Equal(t, "DEBUG", New(Debug).GetLevel())
+	assert.Equal(t, "TRACE", New(Trace).GetLevel())
+	assert.Equal(t, "TRACE", New(Trace, Debug).GetLevel(), "debug doesn't raise trace level")
+	assert.Equal(t, "WARN", New(MinLevel("warn")).GetLevel())
+	assert.Equal(t, "DEBUG", New(MinLevel("WARN"), Debug).GetLevel())
+	assert.Equal(t, "DEBUG", New(Debug, MinLevel("WARN")).GetLevel(), "lowest of options regardless of order")
+	assert.Equal(t, "INFO", New(MinLevel("BLAH")).GetLevel())
+}
+
+func TestLoggerMinLevel(t *testing.T) {
+	rout, rerr := bytes.Buffer{}, bytes.Buffer{}
+	l := New(Out(&rout), Err(&rerr), MinLevel("ERROR"), Format(`{{.Level}} {{.Message}}`))
+	l.fatal = func() {}
+	l.Logf("INFO filtered")
+	l.Logf("WARN filtered")
+	l.Logf("ERROR reported")
+	l.Logf("FATAL reported")
+	assert.Equal(t, "ERROR reported\nFATAL reported\n", rout.String())
+	assert.Equal(t, "ERROR reported\nFATAL reported\n", rerr.String(), "mirrored to err")
+}
+
+func TestSetLevelDefault(t *testing.T) {
+	origDef := def
+	defer func() { def = origDef }()
+
+	buf := bytes.Buffer{}
+	Setup(Out(&buf), Format(`{{.Level}} {{.Message}}`))
+	Printf("DEBUG filtered")
+	require.NoError(t, SetLevel("DEBUG"))
+	assert.Equal(t, "DEBUG", GetLevel())
+	Printf("DEBUG reported")
+	assert.Equal(t, "DEBUG reported\n", buf.String())
+}
+
+func TestLoggerSetLevelConcurrent(t *testing.T) {
+	l := New(Out(&bytes.Buffer{}))
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(2)
+		go func(i int) {
+			defer wg.Done()
+			_ = l.SetLevel(levels[i%4])
+		}(i)
+		go func() {
+			defer wg.Done()
+			l.Logf("DEBUG message")
+		}()
+	}
+	wg.Wait()
+}
+
+func TestLoggerPackageLevel(t *testing.T) {
+	tbl := []struct {
+		name   string
+		levels map[string]string
+		res    string
+	}{
+		{"debug for the package", map[string]string{"go-pkgz/lgr": "DEBUG"}, "DEBUG debug\nINFO  info\nWARN  warn\n"},
+		{"quiet for the package", map[string]string{"lgr": "warn", "other": "TRACE"}, "WARN  warn\n"},
+		{"longest match", map[string]string{"github.com/go-pkgz": "WARN", "go-pkgz/lgr/": "TRACE"},
+			"TRACE trace\nDEBUG debug\nINFO  info\nWARN  warn\n"},
+		{"other package", map[string]string{"pkgz/lgr": "DEBUG", "db": "BAD"}, "INFO  info\nWARN  warn\n"},
+	}
+
+	for _, tt := range tbl {
+		t.Run(tt.name, func(t *testing.T) {
+			buf := bytes.Buffer{}
+			l := New(Out(&buf), Format(`{{.Level}} {{.Message}}`), PackageLevel(tt.levels))
+			l.Logf("TRACE trace")
+			l.Logf("DEBUG debug")
+			l.Logf("INFO info")
+			l.Logf("WARN warn")
+			assert.Equal(t, tt.res, buf.String())
+		})
+	}
+
+	l := New(PackageLevel(map[string]string{"internal/db": "DEBUG", "vendorlib": "WARN"}))
+	assert.True(t, l.pkgEnabled("DEBUG", "example.com/app/internal/db"))
+	assert.True(t, l.pkgEnabled("DEBUG", "example.com/app/internal/db/migrations"), "subpackage")
+	assert.False(t, l.pkgEnabled("DEBUG", "example.com/app/internal/dbx"))
+	assert.False(t, l.pkgEnabled("DEBUG", "example.com/app/internal/db/vendorlib"), "deepest match")
+	assert.False(t, l.pkgEnabled("INFO", "example.com/vendorlib"))
+	assert.True(t, l.pkgEnabled("FATAL", "example.com/vendorlib"))
+	assert.True(t, l.enabled("DEBUG"), "reported for some packages")
+	assert.False(t, l.enabled("TRACE"))
+}
+
+func TestMakeCallerInfoPkgPath(t *testing.T) {
+	tbl := map[string]string{
+		"github.com/go-pkgz/lgr.TestX.func1":      "github.com/go-pkgz/lgr",
+		"github.com/go-pkgz/lgr.(*Logger).Logf":   "github.com/go-pkgz/lgr",
+		"main.main":                               "main",
+		"gopkg.in/yaml%2ev3.(*decoder).unmarshal": "gopkg.in/yaml.v3",
+	}
+	for fn, res := range tbl {
+		assert.Equal(t, res, makeCallerInfo("/src/file.go", 1, fn).PkgPath, fn)
+	}
+}
@@ -0,0 +1,62 @@
+// Package lgrtest provides helpers for testing of lgr output, i.e. normalizing nondeterministic parts
+// of captured logs for golden-file comparisons.
+package lgrtest
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+)
+
+// UpdateEnv is the environment variable to set for updating golden files instead of comparing with them
+const UpdateEnv = "LGRTEST_UPDATE"
+
+var rules = []struct {
+	re   *regexp.Regexp
+	repl string
+}{
+	// RFC3339 timestamps, i.e. 2018-01-07T13:02:34.123Z or 2018-01-07T13:02:34-06:00
+	{regexp.MustCompile(`\d{4}-\d{2}-\d{2}T\d{2}:\d{2}:\d{2}(\.\d+)?(Z|[+-]\d{2}:\d{2})`), "<TIME>"},
+	// lgr timestamps, i.e. 2018/01/07 13:02:34 or 2018/01/07 13:02:34.123
+	{regexp.MustCompile(`\d{4}/\d{2}/\d{2} \d{2}:\d{2}:\d{2}(\.\d+)?`), "<TIME>"},
+	// line numbers of go files, i.e. lgr/logger.go:123
+	{regexp.MustCompile(`(\.go):\d+`), "$1:<LINE>"},
+	// goroutine ids in stack dumps, i.e. "goroutine 12 [running]:"
+	{regexp.MustCompile(`goroutine \d+ \[`), "goroutine <ID> ["},
+	// pc offsets in stack dumps, i.e. +0x1c5
+	{regexp.MustCompile(`\+0x[0-9a-f]+`), "+<PC>"},
+}
+
+// Normalize replaces nondeterministic parts of the output, i.e. timestamps, caller line numbers,
+// goroutine ids and pc offsets, with stable placeholders like <TIME> and <LINE>
+func Normalize(out string) string {
+	for _, r := range rules {
+		out = r.re.ReplaceAllString(out, r.repl)
+	}
+	return out
+}
+
+// Golden compares normalized output with the content of golden file. With LGRTEST_UPDATE env set
+// it writes normalized output to the golden file instead.
+func Golden(t testing.TB, path, out string) {
+	t.Helper()
+	out = Normalize(out)
+	if os.Getenv(UpdateEnv) != "" {
+		if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+			t.Fatalf("can't make golden dir: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(out), 0o600); err != nil {
+			t.Fatalf("can't update golden file: %v", err)
+		}
+		return
+	}
+
+	expected, err := os.ReadFile(path) //nolint:gosec // path set by test
+	if err != nil {
+		t.Fatalf("can't read golden file, set %s=1 to create it: %v", UpdateEnv, err)
+	}
+	if string(expected) != out {
+		t.Errorf("output doesn't match golden file %s\nexpected:\n%s\nactual:\n%s", path, expected, out)
+	}
+}
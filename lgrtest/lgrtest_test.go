@@ -0,0 +1,51 @@
+package lgrtest
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/go-pkgz/lgr"
+)
+
+func TestNormalize(t *testing.T) {
+	tbl := []struct {
+		inp, out string
+	}{
+		{"2018/01/07 13:02:34 INFO  something", "<TIME> INFO  something"},
+		{"2018/01/07 13:02:34.123 DEBUG (lgr/logger.go:123 lgr.Func) msg", "<TIME> DEBUG (lgr/logger.go:<LINE> lgr.Func) msg"},
+		{`{"time":"2018-01-07T13:02:34.123Z","line":"12"}`, `{"time":"<TIME>","line":"12"}`},
+		{"ts 2018-01-07T13:02:34-06:00 x", "ts <TIME> x"},
+		{"goroutine 12 [running]:\n\t/src/main.go:27 +0x1c5", "goroutine <ID> [running]:\n\t/src/main.go:<LINE> +<PC>"},
+		{"no changes 123", "no changes 123"},
+	}
+	for _, tt := range tbl {
+		assert.Equal(t, tt.out, Normalize(tt.inp))
+	}
+}
+
+func TestGolden(t *testing.T) {
+	buf := bytes.Buffer{}
+	l := lgr.New(lgr.Out(&buf), lgr.Msec, lgr.CallerFile)
+	l.Logf("INFO something %d", 123)
+	l.Logf("WARN something else")
+
+	golden := filepath.Join(t.TempDir(), "testdata", "out.golden")
+	t.Setenv(UpdateEnv, "1")
+	Golden(t, golden, buf.String())
+	data, err := os.ReadFile(golden)
+	require.NoError(t, err)
+	assert.Equal(t, "<TIME> INFO  {lgrtest/lgrtest_test.go:<LINE>} something 123\n"+
+		"<TIME> WARN  {lgrtest/lgrtest_test.go:<LINE>} something else\n", string(data))
+
+	t.Setenv(UpdateEnv, "")
+	Golden(t, golden, buf.String())
+
+	mt := &testing.T{}
+	Golden(mt, golden, "different")
+	assert.True(t, mt.Failed())
+}
@@ -15,11 +15,13 @@ import (
 	"io"
 	"os"
 	"path"
+	"reflect"
 	"regexp"
 	"runtime"
 	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"text/template"
 	"time"
 )
@@ -39,6 +41,31 @@ const (
 	FuncDebug = `{{.DT.Format "2006/01/02 15:04:05.000"}} {{.Level}} ({{.CallerFunc}}) {{.Message}}`
 	// FullDebug is WithMsec logging format with caller file, line and function
 	FullDebug = `{{.DT.Format "2006/01/02 15:04:05.000"}} {{.Level}} ({{.CallerFile}}:{{.CallerLine}} {{.CallerFunc}}) {{.Message}}`
+	// GCP is JSON logging format of Google Cloud Logging, with severity, source location of the caller and fields
+	GCP = `{"severity":"{{gcpSeverity .LevelRaw}}","time":"{{.DT.Format "2006-01-02T15:04:05.999999999Z07:00"}}",` +
+		`"message":{{json .Message}}{{if .CallerFile}},"logging.googleapis.com/sourceLocation":` +
+		`{"file":{{json .CallerFile}},"line":"{{.CallerLine}}","function":{{json .CallerFunc}}}{{end}}` +
+		`{{range .Fields}},{{json .Key}}:{{jsonValue .Val}}{{end}}}`
+	// TAP is Short logging format with lines prefixed by "# ", safe to interleave with TAP and go test output
+	TAP = `# {{.DT.Format "2006/01/02 15:04:05"}} {{.Level}} {{tap .Message}}`
+	// GitHubActions is Short logging format with WARN and ERROR+ records written as GitHub Actions workflow commands,
+	// i.e. "::error file=cmd/app/main.go,line=12::message", to make annotations for the caller. File path is relative
+	// to GITHUB_WORKSPACE, i.e. the repository root, as GitHub requires to show annotations in PR files.
+	GitHubActions = `{{with ghCommand .LevelRaw}}::{{.}} file={{ghProp (ghFile $.CallerPath)}},line={{$.CallerLine}}::` +
+		`{{ghData $.Message}}{{else}}{{.DT.Format "2006/01/02 15:04:05"}} {{.Level}} {{.Message}}{{end}}`
+	// Logfmt is logfmt logging format, key=value pairs with values quoted as needed, caller and fields, i.e.
+	// time=2018-01-07T13:02:34.123Z level=info caller=foo/bar.go:89 func=bar.myFunc msg="some message 123" user=joe
+	Logfmt = `time={{.DT.Format "2006-01-02T15:04:05.000Z07:00"}} level={{lower .LevelRaw}}` +
+		`{{if .CallerFile}} caller={{logfmt (printf "%s:%d" .CallerFile .CallerLine)}}{{end}}` +
+		`{{if .CallerFunc}} func={{logfmt .CallerFunc}}{{end}} msg={{logfmt .Message}}{{with .Fields}} {{.}}{{end}}`
+	// Zap is JSON logging format with keys of zap's production encoder: level, ts (epoch seconds), caller, msg and fields
+	Zap = `{"level":"{{zapLevel .LevelRaw}}","ts":{{epoch .DT}}` +
+		`{{if .CallerFile}},"caller":{{json (printf "%s:%d" .CallerFile .CallerLine)}}{{end}},"msg":{{json .Message}}` +
+		`{{range .Fields}},{{json .Key}}:{{jsonValue .Val}}{{end}}}`
+	// Zerolog is JSON logging format with zerolog's default keys: level, time (RFC 3339), caller, message and fields
+	Zerolog = `{"level":"{{lower .LevelRaw}}","time":"{{.DT.Format "2006-01-02T15:04:05Z07:00"}}"` +
+		`{{if .CallerFile}},"caller":{{json (printf "%s:%d" .CallerFile .CallerLine)}}{{end}},"message":{{json .Message}}` +
+		`{{range .Fields}},{{json .Key}}:{{jsonValue .Val}}{{end}}}`
 )
 
 var secretReplacement = []byte("******")
@@ -51,44 +78,96 @@ var (
 // Logger provided simple logger with basic support of levels. Thread safe
 type Logger struct {
 	// set with Option calls
-	stdout, stderr io.Writer // destination writes for out and err
-	sameStream     bool      // stdout and stderr are the same stream
-	dbg            bool      // allows reporting for DEBUG level
-	trace          bool      // allows reporting for TRACE and DEBUG levels
-	callerFile     bool      // reports caller file with line number, i.e. foo/bar.go:89
-	callerFunc     bool      // reports caller function name, i.e. bar.myFunc
-	callerPkg      bool      // reports caller package name
-	levelBraces    bool      // encloses level with [], i.e. [INFO]
-	callerDepth    int       // how many stack frames to skip, relative to the real (reported) frame
-	format         string    // layout template
-	secrets        [][]byte  // sub-strings to secrets by matching
-	mapper         Mapper    // map (alter) output based on levels
+	stdout, stderr io.Writer             // destination writes for out and err
+	sameStream     bool                  // stdout and stderr are the same stream
+	level          *int32                // lowest reported level, index in levels, atomic, shared with child loggers
+	pkgLevels      map[string]int        // lowest reported level per package path, index in levels, overrides level
+	callerFile     bool                  // reports caller file with line number, i.e. foo/bar.go:89
+	callerFunc     bool                  // reports caller function name, i.e. bar.myFunc
+	callerPkg      bool                  // reports caller package name
+	levelBraces    bool                  // encloses level with [], i.e. [INFO]
+	levelPad       LevelPadding          // alignment of short levels
+	prefix         string                // added to each message
+	indent         *int32                // indentation level of messages, atomic, shared with child loggers
+	status         *statusLine           // transient status line on TTY, guarded by lock, shared with child loggers
+	buildBanner    bool                  // log build info record on New
+	noop           bool                  // drops all messages, see NoOpLogger
+	forward        forwardFn             // hands messages off to another logger, see UseSlogDefault
+	callerDepth    int                   // how many stack frames to skip, relative to the real (reported) frame
+	format         string                // layout template
+	formatErr      string                // layout template for err writer, format used if empty
+	tz             *time.Location        // time zone of records, local if nil
+	tzErr          *time.Location        // time zone of records mirrored to err writer, tz used if nil
+	secrets        [][]byte              // sub-strings to secrets by matching
+	scrub          scrubFn               // user-defined message scrubber, applied before any output
+	mapper         Mapper                // map (alter) output based on levels
+	mapErr         *Mapper               // mapper for err writer, mapper used if nil
+	crashDir       string                // directory for crash reports on PANIC and FATAL
+	exitHooks      []func()              // called on FATAL and PANIC before exit
+	exitTimeout    time.Duration         // max time for exit hooks and flush
+	writeTimeout   time.Duration         // max time for each write, no limit if 0
+	eol            string                // record terminator, "\n" by default
+	wrapWidth      int                   // soft wrap width for terminal writers, detected if -1, off if 0
+	tags           []string              // tags added to each message, set by Tagged
+	fields         Fields                // key-value pairs added to each record, set by With and WithFields
+	mutedTags      map[string]bool       // messages with any of these tags are dropped
+	tagRoutes      map[string]tagRoute   // tagged messages written to the route's writer instead of stdout
+	tagSampling    map[string]*tagSample // tagged messages sampled, 1 of N passed
+	escalator      *escalator            // escalates repeated messages
+	clock          *clockMonitor         // detects wall clock jumping backwards, shared with child loggers
+	events         map[string]string     // message catalog, event id -> format, set by Events
+	localeEvents   eventLocales          // localized message catalogs, locale -> event id -> format
+	locale         string                // locale of message catalog, set by Locale
+	errOrder       ErrOrdering           // order of stdout and stderr writes for ERROR, FATAL and PANIC
+	onError        func(err error)       // called on internal errors, like template execution failure
+	writers        *sync.Map             // cached per-level writers, level -> *Writer
 
 	// internal use
-	now           nowFn
-	fatal         panicFn
-	msec          bool
-	lock          sync.Mutex
-	callerOn      bool
-	levelBracesOn bool
-	errorDump     bool
-	templ         *template.Template
-	reTrace       *regexp.Regexp
+	now               nowFn
+	fatal             panicFn
+	msec              bool
+	lock              *sync.Mutex // pointer, shared with child loggers
+	callerOn          bool
+	levelBracesOn     bool
+	fieldsInFormat    bool // format places fields itself, with included formats
+	tagsInFormat      bool // format places tags itself, with included formats
+	fieldsInFormatErr bool // the same as fieldsInFormat for formatErr
+	tagsInFormatErr   bool // the same as tagsInFormat for formatErr
+	errorDump         bool
+	wrapOut           bool
+	wrapErr           bool
+	templ             *template.Template
+	templErr          *template.Template
+	reTrace           *regexp.Regexp
 }
 
 // can be redefined internally for testing
 type nowFn func() time.Time
 type panicFn func()
 
+// scrubFn returns scrubbed message and false if the message should be dropped
+type scrubFn func(msg string) (string, bool)
+
+// forwardFn receives parsed level, message and caller's program counter
+type forwardFn func(lv, msg string, pc uintptr)
+
 // layout holds all parts to construct the final message with template or with individual flags
 type layout struct {
-	DT         time.Time
-	Level      string
-	Message    string
-	CallerPkg  string
-	CallerFile string
-	CallerFunc string
-	CallerLine int
+	DT          time.Time
+	Level       string // level padded to 5 chars, i.e. "INFO "
+	LevelRaw    string // level without padding, i.e. "INFO"
+	LevelNum    int    // syslog-style numeric level, i.e. 6 for INFO
+	LevelBraces string // level enclosed with [] and padded to 7 chars, i.e. "[INFO] "
+	Message     string
+	CallerPkg   string
+	CallerFile  string
+	CallerFunc  string
+	CallerLine  int
+	CallerPath  string // full path of caller file, i.e. /src/app/cmd/main.go
+	Tags        string // tags joined with space, i.e. "#db #slow"
+	Fields      Fields // fields added by With, {{.Fields}} renders them as "key=value" pairs
+
+	msgStart int // offset of the message text in Message, after indentation and prefix
 }
 
 // New makes new leveled logger. By default writes to stdout/stderr.
@@ -101,37 +180,104 @@ func New(options ...Option) *Logger {
 		stdout:      os.Stdout,
 		stderr:      os.Stderr,
 		callerDepth: 0,
+		exitTimeout: time.Second,
+		eol:         "\n",
 		mapper:      nopMapper,
 		reTrace:     reTraceDefault,
+		lock:        &sync.Mutex{},
+		writers:     &sync.Map{},
+		indent:      new(int32),
+		level:       newLevel(""), // set by Debug, Trace and MinLevel options, INFO if none used
+		status:      &statusLine{},
 	}
 	for _, opt := range options {
 		opt(&res)
 	}
+	if *res.level == levelUnset {
+		*res.level = int32(levelIndex("INFO"))
+	}
 
-	if res.format != "" {
-		// formatter defined
-		var err error
-		res.templ, err = template.New("lgr").Parse(res.format)
-		if err != nil {
-			fmt.Printf("invalid template %s, error %v. switched to %s\n", res.format, err, Short)
-			res.format = Short
-			res.templ = template.Must(template.New("lgrDefault").Parse(Short))
+	if res.format != "" { // formatter defined
+		res.format, res.templ = res.parseFormat(res.format)
+	}
+	if res.formatErr != "" {
+		res.formatErr, res.templErr = res.parseFormat(res.formatErr)
+	}
+
+	// set *On flags once for optimization on multiple Logf calls
+	formats := withIncludedFormats(res.format + res.formatErr)
+	res.callerOn = strings.Contains(formats, ".Caller") || res.callerFile || res.callerFunc || res.callerPkg
+	res.levelBracesOn = strings.Contains(formats, ".LevelBraces")
+	included := withIncludedFormats(res.format)
+	res.fieldsInFormat, res.tagsInFormat = strings.Contains(included, ".Fields"), strings.Contains(included, ".Tags")
+	included = withIncludedFormats(res.formatErr)
+	res.fieldsInFormatErr, res.tagsInFormatErr = strings.Contains(included, ".Fields"), strings.Contains(included, ".Tags")
+
+	res.sameStream = isStreamsSame(res.stdout, res.stderr)
+	res.status.tty = isTerminal(res.stdout)
+	if res.wrapWidth != 0 {
+		res.wrapOut, res.wrapErr = res.status.tty, isTerminal(res.stderr)
+		if res.wrapWidth < 0 && res.wrapOut {
+			res.wrapWidth = consoleWidth(res.stdout)
+		}
+		if res.wrapWidth < 0 {
+			res.wrapWidth = consoleWidth(res.stderr)
 		}
+	}
 
-		buf := bytes.Buffer{}
-		if err = res.templ.Execute(&buf, layout{}); err != nil {
-			fmt.Printf("failed to execute template %s, error %v. switched to %s\n", res.format, err, Short)
-			res.format = Short
-			res.templ = template.Must(template.New("lgrDefault").Parse(Short))
+	if res.writeTimeout > 0 {
+		res.stdout = &timeoutWriter{w: res.stdout, timeout: res.writeTimeout}
+		res.stderr = &timeoutWriter{w: res.stderr, timeout: res.writeTimeout}
+	}
+
+	if res.buildBanner {
+		res.logf("INFO build info, %s", formatFields(BuildInfoFields()))
+	}
+	for tag, r := range res.tagRoutes {
+		r.sameStream = isStreamsSame(r.out, res.stderr)
+		if res.writeTimeout > 0 {
+			r.out = &timeoutWriter{w: r.out, timeout: res.writeTimeout}
 		}
+		res.tagRoutes[tag] = r
 	}
 
-	// set *On flags once for optimization on multiple Logf calls
-	res.callerOn = strings.Contains(res.format, "{{.Caller") || res.callerFile || res.callerFunc || res.callerPkg
-	res.levelBracesOn = strings.Contains(res.format, "[{{.Level}}]") || res.levelBraces
+	return &res
+}
 
-	res.sameStream = isStreamsSame(res.stdout, res.stderr)
+// parseFormat makes template for the format or the name of registered format, invalid format switched to Short.
+// The problem reported to OnError func, if defined, otherwise to err writer.
+func (l *Logger) parseFormat(format string) (string, *template.Template) {
+	if f, ok := registeredFormat(format); ok {
+		format = f
+	}
+	format = strings.ReplaceAll(format, "[{{.Level}}]", "{{.LevelBraces}}") // padding-aware braces
+	templ, err := newTemplate(format)
+	if err != nil {
+		l.formatError(fmt.Errorf("lgr: invalid template %s, %w. switched to %s", format, err, Short))
+		return Short, template.Must(template.New("lgrDefault").Parse(Short))
+	}
 
+	buf := bytes.Buffer{}
+	if err = templ.Execute(&buf, layout{}); err != nil {
+		l.formatError(fmt.Errorf("lgr: failed to execute template %s, %w. switched to %s", format, err, Short))
+		return Short, template.Must(template.New("lgrDefault").Parse(Short))
+	}
+	return format, templ
+}
+
+// formatError reports invalid format to OnError func, if defined, otherwise to err writer
+func (l *Logger) formatError(err error) {
+	if l.onError != nil {
+		l.onError(err)
+		return
+	}
+	_, _ = fmt.Fprintln(l.stderr, err)
+}
+
+// clone makes child logger sharing writers, lock and runtime state with the parent
+func (l *Logger) clone() *Logger {
+	res := *l
+	res.writers = &sync.Map{} // cached writers bound to the parent
 	return &res
 }
 
@@ -144,89 +290,258 @@ func (l *Logger) Logf(format string, args ...interface{}) {
 	l.logf(format, args...)
 }
 
-// nolint gocyclo
+// Debugf logs message at DEBUG level, implements LeveledL
+func (l *Logger) Debugf(format string, args ...interface{}) { l.logf("DEBUG "+format, args...) }
+
+// Infof logs message at INFO level, implements LeveledL
+func (l *Logger) Infof(format string, args ...interface{}) { l.logf("INFO "+format, args...) }
+
+// Warnf logs message at WARN level, implements LeveledL
+func (l *Logger) Warnf(format string, args ...interface{}) { l.logf("WARN "+format, args...) }
+
+// Errorf logs message at ERROR level, implements LeveledL
+func (l *Logger) Errorf(format string, args ...interface{}) { l.logf("ERROR "+format, args...) }
+
 func (l *Logger) logf(format string, args ...interface{}) {
+	if l.noop {
+		return
+	}
 
 	var lv, msg string
 	if len(args) == 0 {
-		lv, msg = l.extractLevel(format)
+		lv, msg = ParseLevel(format)
 	} else {
-		lv, msg = l.extractLevel(fmt.Sprintf(format, args...))
+		lv, msg = ParseLevel(fmt.Sprintf(format, args...))
 	}
+	l.log(lv, msg, format, 0)
+}
 
-	if lv == "DEBUG" && !l.dbg {
+// log makes and writes the record for parsed level and message. The key identifies the message for escalation
+// and pc, if not 0, defines the caller instead of the stack frame of logf caller.
+// nolint gocyclo
+func (l *Logger) log(lv, msg, key string, pc uintptr) {
+	fields := l.fields
+	if l.scrub != nil {
+		var keep bool
+		if msg, keep = l.scrub(msg); !keep {
+			return
+		}
+		if fields, keep = l.scrubFields(); !keep {
+			return
+		}
+	}
+
+	if l.forward != nil { // filtering and output done by the receiving logger
+		if pc == 0 {
+			pc = callerPC(l.callerDepth)
+		}
+		l.forward(lv, msg, pc)
+		if lv == "FATAL" || lv == "PANIC" {
+			l.exit()
+		}
 		return
 	}
-	if lv == "TRACE" && !l.trace {
+
+	if len(l.pkgLevels) > 0 { // per-package level needs the caller before any other processing
+		if pc == 0 {
+			pc = callerPC(l.callerDepth)
+		}
+		if !l.pkgEnabled(lv, callerFromPC(pc).PkgPath) {
+			return
+		}
+	} else if !l.enabled(lv) {
+		return
+	}
+
+	msg, tags := l.extractTags(msg)
+	if l.isMuted(tags) || !l.sampled(tags) {
 		return
 	}
 
+	dt := l.now()
+	if l.escalator != nil {
+		lv = l.escalator.check(lv, key, dt)
+	}
+
 	var ci callerInfo
 	if l.callerOn { // optimization to avoid expensive caller evaluation if caller info not in the template
-		ci = l.reportCaller(l.callerDepth)
+		if pc == 0 {
+			pc = callerPC(l.callerDepth)
+		}
+		ci = callerFromPC(pc)
+	}
+
+	if l.clock != nil {
+		if skew := l.clock.check(dt); skew > 0 { // warning reported before the record, with the same caller
+			l.log("WARN", fmt.Sprintf("lgr: clock moved backwards by %v", skew), "lgr: clock skew", pc)
+		}
+	}
+
+	levelBraces := ""
+	if l.levelBracesOn {
+		levelBraces = l.formatLevelBraces(lv)
+	}
+
+	if l.tz != nil {
+		dt = dt.In(l.tz)
 	}
 
+	indent := l.indentation() + l.prefix
 	elems := layout{
-		DT:         l.now(),
-		Level:      l.formatLevel(lv),
-		Message:    strings.TrimSuffix(msg, "\n"), // output adds EOL, trim from the message if passed
-		CallerFunc: ci.FuncName,
-		CallerFile: ci.File,
-		CallerPkg:  ci.Pkg,
-		CallerLine: ci.Line,
+		DT:          dt,
+		Level:       l.formatLevel(lv),
+		LevelRaw:    lv,
+		LevelNum:    levelNum(lv),
+		LevelBraces: levelBraces,
+		Message:     indent + strings.TrimSuffix(msg, "\n"), // output adds EOL, trim from the message
+		CallerFunc:  ci.FuncName,
+		CallerFile:  ci.File,
+		CallerPkg:   ci.Pkg,
+		CallerLine:  ci.Line,
+		CallerPath:  ci.Path,
+		Tags:        formatTags(tags),
+		Fields:      fields,
+		msgStart:    len(indent),
 	}
 
-	var data []byte
-	if l.format == "" {
-		data = []byte(l.formatWithOptions(elems))
-	} else {
-		buf := bytes.Buffer{}
-		err := l.templ.Execute(&buf, elems) // once constructed, a template may be executed safely in parallel.
-		if err != nil {
-			fmt.Printf("failed to execute template, %v\n", err) // should never happen
-		}
-		data = buf.Bytes()
+	data := l.hideSecrets(l.render(elems))
+
+	out, sameStream := l.stdout, l.sameStream
+	r, routed := l.tagRoute(tags)
+	if routed {
+		out, sameStream = r.out, r.sameStream
 	}
-	data = append(data, '\n')
 
-	if l.levelBracesOn { // rearrange space in short levels
-		data = bytes.Replace(data, []byte("[WARN ]"), []byte("[WARN] "), 1)
-		data = bytes.Replace(data, []byte("[INFO ]"), []byte("[INFO] "), 1)
+	// write to err as well for high levels, in the order defined by ErrOrder
+	mirror := !sameStream && (lv == "ERROR" || lv == "FATAL" || lv == "PANIC")
+	errData := data
+	if mirror {
+		errData = l.renderErr(elems, data)
+	}
+	outData := data
+	if l.wrapOut && !routed {
+		outData = l.wrapRecord(data, elems.Message)
+	}
+	if l.wrapErr && mirror {
+		errData = l.wrapRecord(errData, elems.Message)
 	}
-	data = l.hideSecrets(data)
 
 	l.lock.Lock()
-	_, _ = l.stdout.Write(data)
+	if l.status.active && !routed {
+		l.status.clear(out)
+	}
 
-	// write to err as well for high levels, exit(1) on fatal and panic and dump stack on panic level
+	if mirror && l.errOrder != ErrAfterOut {
+		_, _ = l.stderr.Write(errData)
+	}
+	if !mirror || l.errOrder != ErrInsteadOfOut {
+		_, _ = out.Write(outData)
+	}
+	if mirror && l.errOrder == ErrAfterOut {
+		_, _ = l.stderr.Write(errData)
+	}
+
+	// exit(1) on fatal and panic and dump stack on panic level
+	exit := false
 	switch lv {
 	case "ERROR":
-		if !l.sameStream {
-			_, _ = l.stderr.Write(data)
-		}
 		if l.errorDump {
 			stackInfo := make([]byte, 1024*1024)
 			if stackSize := runtime.Stack(stackInfo, false); stackSize > 0 {
 				traceLines := l.reTrace.Split(string(stackInfo[:stackSize]), -1)
 				if len(traceLines) > 0 {
-					_, _ = l.stdout.Write([]byte(">>> stack trace:\n" + traceLines[len(traceLines)-1]))
+					_, _ = out.Write([]byte(">>> stack trace:\n" + traceLines[len(traceLines)-1]))
 				}
 			}
 		}
 	case "FATAL":
-		if !l.sameStream {
-			_, _ = l.stderr.Write(data)
+		if l.crashDir != "" {
+			l.writeCrashReport(elems.DT, data)
 		}
-		l.fatal()
+		exit = true
 	case "PANIC":
-		if !l.sameStream {
-			_, _ = l.stderr.Write(data)
-		}
 		_, _ = l.stderr.Write(getDump())
-		l.fatal()
+		if l.crashDir != "" {
+			l.writeCrashReport(elems.DT, data)
+		}
+		exit = true
 	}
 
 	l.lock.Unlock()
+
+	if exit {
+		l.exit() // called outside of lock to allow exit hooks to log
+	}
+}
+
+// exit runs exit hooks and flushes writers, bounded by exitTimeout, and only then calls fatal.
+// Does nothing for noop logger, so direct callers like Std().Fatal don't exit either.
+func (l *Logger) exit() {
+	if l.noop {
+		return
+	}
+	done := make(chan struct{})
+	go func() {
+		for _, h := range l.exitHooks {
+			h()
+		}
+		l.lock.Lock()
+		flushWriter(l.stdout)
+		if !l.sameStream {
+			flushWriter(l.stderr)
+		}
+		l.lock.Unlock()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(l.exitTimeout):
+		_, _ = fmt.Fprintf(os.Stderr, "exit hooks and flush not completed in %v\n", l.exitTimeout)
+	}
+	l.fatal()
+}
+
+// flushWriter flushes buffered writers and syncs files, errors ignored as nothing can be done on exit
+func flushWriter(w io.Writer) {
+	switch v := w.(type) {
+	case interface{ Flush() error }:
+		_ = v.Flush()
+	case interface{ Sync() error }:
+		_ = v.Sync()
+	case interface{ Unwrap() io.Writer }:
+		flushWriter(v.Unwrap())
+	case interface{ Unwrap() []io.Writer }:
+		for _, u := range v.Unwrap() {
+			flushWriter(u)
+		}
+	}
+}
+
+// Indent increases indentation of subsequent messages, useful for console output of nested operations.
+// Returns func to decrease it back, i.e. defer l.Indent()(). Indentation shared with child loggers.
+func (l *Logger) Indent() (outdent func()) {
+	atomic.AddInt32(l.indent, 1)
+	var once sync.Once
+	return func() { once.Do(l.Outdent) }
+}
+
+// Outdent decreases indentation of subsequent messages
+func (l *Logger) Outdent() {
+	for {
+		v := atomic.LoadInt32(l.indent)
+		if v <= 0 || atomic.CompareAndSwapInt32(l.indent, v, v-1) {
+			return
+		}
+	}
+}
+
+// indentation returns two spaces for each indentation level
+func (l *Logger) indentation() string {
+	if v := atomic.LoadInt32(l.indent); v > 0 {
+		return strings.Repeat("  ", int(v))
+	}
+	return ""
 }
 
 func (l *Logger) hideSecrets(data []byte) []byte {
@@ -241,37 +556,50 @@ type callerInfo struct {
 	Line     int
 	FuncName string
 	Pkg      string
+	PkgPath  string // import path of the package, i.e. github.com/go-pkgz/lgr
+	Path     string // full path of the file
 }
 
-// calldepth 0 identifying the caller of reportCaller()
-func (l *Logger) reportCaller(calldepth int) (res callerInfo) {
-
-	// caller gets file, line number abd function name via runtime.Callers
-	// file looks like /go/src/github.com/go-pkgz/lgr/logger.go
-	// file is an empty string if not known.
-	// funcName looks like:
-	//   main.Test
-	//   foo/bar.Test
-	//   foo/bar.Test.func1
-	//   foo/bar.(*Bar).Test
-	//   foo/bar.glob..func1
-	// funcName is an empty string if not known.
-	// line is a zero if not known.
-	caller := func(calldepth int) (file string, line int, funcName string) {
-		pcs := make([]uintptr, 1)
-		n := runtime.Callers(calldepth, pcs)
-		if n != 1 {
-			return "", 0, ""
-		}
-
-		frame, _ := runtime.CallersFrames(pcs).Next()
+// callerPC returns program counter of the reported caller, calldepth 0 identifying the caller of the logger's method
+func callerPC(calldepth int) uintptr {
+	pcs := make([]uintptr, 1)
+	// add 5 to adjust stack level because it was called from 3 nested functions added by lgr, i.e. callerPC,
+	// log and logf, plus the logger's method and runtime.Callers itself
+	if n := runtime.Callers(calldepth+5, pcs); n != 1 {
+		return 0
+	}
+	return pcs[0]
+}
 
-		return frame.File, frame.Line, frame.Function
+// callerFromPC gets file, line number and function name for the program counter
+// file looks like /go/src/github.com/go-pkgz/lgr/logger.go
+// funcName looks like:
+//
+//	main.Test
+//	foo/bar.Test
+//	foo/bar.Test.func1
+//	foo/bar.(*Bar).Test
+//	foo/bar.glob..func1
+//
+// empty callerInfo returned if any of them is not known.
+// Results cached by pc, so strings of caller info interned and repeated records from the same line don't make garbage.
+func callerFromPC(pc uintptr) callerInfo {
+	if pc == 0 {
+		return callerInfo{}
+	}
+	if ci, ok := callerCache.Load(pc); ok {
+		return ci.(callerInfo)
 	}
+	frame, _ := runtime.CallersFrames([]uintptr{pc}).Next()
+	ci := makeCallerInfo(frame.File, frame.Line, frame.Function)
+	callerCache.Store(pc, ci)
+	return ci
+}
 
-	// add 5 to adjust stack level because it was called from 3 nested functions added by lgr, i.e. caller,
-	// reportCaller and logf, plus 2 frames by runtime
-	filePath, line, funcName := caller(calldepth + 2 + 3)
+// callerCache keeps callerInfo by pc, limited by the number of logging calls in the code
+var callerCache sync.Map
+
+func makeCallerInfo(filePath string, line int, funcName string) (res callerInfo) {
 	if (filePath == "") || (line <= 0) || (funcName == "") {
 		return callerInfo{}
 	}
@@ -279,7 +607,7 @@ func (l *Logger) reportCaller(calldepth int) (res callerInfo) {
 	_, pkgInfo := path.Split(path.Dir(filePath))
 	res.Pkg = strings.Split(pkgInfo, "@")[0] // remove version from package name
 
-	res.File = filePath
+	res.File, res.Path = filePath, filePath
 	if pathElems := strings.Split(filePath, "/"); len(pathElems) > 2 {
 		res.File = strings.Join(pathElems[len(pathElems)-2:], "/")
 	}
@@ -287,10 +615,77 @@ func (l *Logger) reportCaller(calldepth int) (res callerInfo) {
 
 	funcNameElems := strings.Split(funcName, "/")
 	res.FuncName = funcNameElems[len(funcNameElems)-1]
+	res.PkgPath = funcName
+	if dot := strings.IndexByte(res.FuncName, '.'); dot >= 0 { // dots of the last path element escaped as %2e
+		res.PkgPath = strings.ReplaceAll(funcName[:len(funcName)-len(res.FuncName)+dot], "%2e", ".")
+	}
 
 	return res
 }
 
+// render makes the record line with template or with individual options. On template failure or panic in user-provided
+// funcs, like mapper, the record rendered with the plain format, followed by an internal ERROR record about the problem.
+// The problem also reported to OnError func, if defined.
+func (l *Logger) render(elems layout) (res []byte) {
+	defer func() {
+		if r := recover(); r != nil {
+			res = l.renderFallback(elems, fmt.Errorf("lgr: formatter panic, %v", r))
+		}
+	}()
+
+	if len(elems.Fields) > 0 && !l.fieldsInFormat { // format doesn't place fields by itself
+		elems.Message += " " + elems.Fields.String()
+	}
+
+	if l.format == "" {
+		return []byte(l.formatWithOptions(elems) + l.eol)
+	}
+	if elems.Tags != "" && !l.tagsInFormat { // format doesn't place tags, keep them in message
+		elems.Message = elems.Message[:elems.msgStart] + elems.Tags + " " + elems.Message[elems.msgStart:]
+	}
+	buf := bytes.Buffer{}
+	// once constructed, a template may be executed safely in parallel.
+	if err := l.templ.Execute(&buf, elems); err != nil {
+		return l.renderFallback(elems, fmt.Errorf("lgr: failed to execute template, %w", err))
+	}
+	buf.WriteString(l.eol)
+	return buf.Bytes()
+}
+
+// renderErr makes the record line for err writer, with MapErr mapper, FormatErr template and TimeZoneErr location
+// if defined, otherwise returns data for out
+func (l *Logger) renderErr(elems layout, data []byte) []byte {
+	if l.mapErr == nil && l.templErr == nil && l.tzErr == nil {
+		return data
+	}
+	if l.tzErr != nil {
+		elems.DT = elems.DT.In(l.tzErr)
+	}
+	errLogger := *l
+	if l.mapErr != nil {
+		errLogger.mapper = *l.mapErr
+	}
+	if l.templErr != nil {
+		errLogger.format, errLogger.templ = l.formatErr, l.templErr
+		errLogger.fieldsInFormat, errLogger.tagsInFormat = l.fieldsInFormatErr, l.tagsInFormatErr
+	}
+	return l.hideSecrets(errLogger.render(elems))
+}
+
+// renderFallback makes the record line with the plain format and adds the internal ERROR record with err
+func (l *Logger) renderFallback(elems layout, err error) []byte {
+	if l.onError != nil {
+		l.onError(err)
+	}
+	errElems := layout{DT: elems.DT, Level: "ERROR", Message: err.Error()}
+	return []byte(plainFormat(elems) + l.eol + plainFormat(errElems) + l.eol)
+}
+
+// plainFormat formats the record as Short layout does, without template and mapper
+func plainFormat(elems layout) string {
+	return elems.DT.Format("2006/01/02 15:04:05") + " " + elems.Level + " " + elems.Message
+}
+
 // speed-optimized version of formatter, used with individual options only, i.e. without Format call
 func (l *Logger) formatWithOptions(elems layout) (res string) {
 
@@ -304,17 +699,23 @@ func (l *Logger) formatWithOptions(elems layout) (res string) {
 
 	parts := make([]string, 0, 4)
 
-	parts = append(
-		parts,
-		l.mapper.TimeFunc(orElse(l.msec,
-			func() string { return elems.DT.Format("2006/01/02 15:04:05.000") },
-			func() string { return elems.DT.Format("2006/01/02 15:04:05") },
-		)),
-		l.levelMapper(elems.Level)(orElse(l.levelBraces,
-			func() string { return `[` + elems.Level + `]` },
-			func() string { return elems.Level },
-		)),
+	level := orElse(l.levelBraces,
+		func() string { return l.formatLevelBraces(strings.TrimSpace(elems.Level)) },
+		func() string { return elems.Level },
 	)
+	if l.mapper.LevelMapFunc != nil {
+		level = l.mapper.LevelMapFunc(level)
+	}
+
+	dt := orElse(l.msec,
+		func() string { return elems.DT.Format("2006/01/02 15:04:05.000") },
+		func() string { return elems.DT.Format("2006/01/02 15:04:05") },
+	)
+	if l.mapper.TimeFunc != nil {
+		dt = l.mapper.TimeFunc(dt)
+	}
+
+	parts = append(parts, dt, l.levelMapper(elems.Level)(level))
 
 	if l.callerFile || l.callerFunc || l.callerPkg {
 		var callerParts []string
@@ -336,6 +737,10 @@ func (l *Logger) formatWithOptions(elems layout) (res string) {
 		parts = append(parts, caller)
 	}
 
+	if elems.Tags != "" {
+		parts = append(parts, elems.Tags)
+	}
+
 	msg := elems.Message
 	if l.mapper.MessageFunc != nil {
 		msg = l.mapper.MessageFunc(elems.Message)
@@ -347,19 +752,75 @@ func (l *Logger) formatWithOptions(elems layout) (res string) {
 
 // formatLevel aligns level to 5 chars
 func (l *Logger) formatLevel(lv string) string {
+	return l.padLevel(lv, 5)
+}
+
+// formatLevelBraces encloses level with [] and aligns to 7 chars, i.e. "[INFO] "
+func (l *Logger) formatLevelBraces(lv string) string {
+	if braced, ok := bracedLevels[lv]; ok {
+		return l.padLevel(braced, 7)
+	}
+	return l.padLevel("["+lv+"]", 7)
+}
+
+// padLevel aligns level to the width according to levelPad
+func (l *Logger) padLevel(lv string, width int) string {
+	if len(lv) >= width || l.levelPad == LevelPadNone {
+		return lv
+	}
+	if padded, ok := paddedLevels[l.levelPad][lv]; ok && len(padded) == width {
+		return padded
+	}
+	if l.levelPad == LevelPadLeft {
+		return strings.Repeat(" ", width-len(lv)) + lv
+	}
+	return lv + strings.Repeat(" ", width-len(lv))
+}
 
-	spaces := ""
-	if len(lv) == 4 {
-		spaces = " "
+// bracedLevels and paddedLevels keep interned forms of known levels, to avoid allocations on each record
+var bracedLevels, paddedLevels = func() (map[string]string, map[LevelPadding]map[string]string) {
+	braced := map[string]string{}
+	padded := map[LevelPadding]map[string]string{LevelPadRight: {}, LevelPadLeft: {}}
+	for _, lv := range levels {
+		braced[lv] = "[" + lv + "]"
+		for _, v := range []struct {
+			lv    string
+			width int
+		}{{lv, 5}, {braced[lv], 7}} {
+			fill := strings.Repeat(" ", v.width-len(v.lv))
+			padded[LevelPadRight][v.lv] = v.lv + fill
+			padded[LevelPadLeft][v.lv] = fill + v.lv
+		}
+	}
+	return braced, padded
+}()
+
+// levelNum returns syslog severity for the level
+func levelNum(lv string) int {
+	switch lv {
+	case "TRACE", "DEBUG":
+		return 7
+	case "INFO":
+		return 6
+	case "WARN":
+		return 4
+	case "ERROR":
+		return 3
+	case "FATAL":
+		return 2
+	case "PANIC":
+		return 1
 	}
-	return lv + spaces
+	return 6
 }
 
-// extractLevel parses messages with optional level prefix and returns level and the message with stripped level
-func (l *Logger) extractLevel(line string) (level, msg string) {
+// ParseLevel parses message with optional level prefix, i.e. "INFO msg", "INFO: msg" or "[INFO] msg", and returns
+// level and the message with stripped level. Bare level counts only if followed by the end of line, whitespace or ":",
+// so "WARNING: disk full" is not split. Level is INFO for messages without a known prefix.
+func ParseLevel(line string) (level, msg string) {
 	for _, lv := range levels {
-		if strings.HasPrefix(line, lv) {
-			return lv, strings.TrimSpace(line[len(lv):])
+		if strings.HasPrefix(line, lv) && isLevelDelim(line[len(lv):]) {
+			return lv, strings.TrimSpace(strings.TrimPrefix(line[len(lv):], ":"))
 		}
 		if strings.HasPrefix(line, "["+lv+"]") {
 			return lv, strings.TrimSpace(line[len("["+lv+"]"):])
@@ -368,7 +829,20 @@ func (l *Logger) extractLevel(line string) (level, msg string) {
 	return "INFO", line
 }
 
+// isLevelDelim checks if the rest of line after bare level is empty or starts with whitespace or ":"
+func isLevelDelim(rest string) bool {
+	if rest == "" {
+		return true
+	}
+	switch rest[0] {
+	case ' ', '\t', '\n', '\r', ':':
+		return true
+	}
+	return false
+}
+
 func (l *Logger) levelMapper(level string) mapFunc {
+	level = strings.TrimSpace(level)
 
 	nop := func(s string) string {
 		return s
@@ -380,12 +854,12 @@ func (l *Logger) levelMapper(level string) mapFunc {
 			return nop
 		}
 		return l.mapper.DebugFunc
-	case "INFO ":
+	case "INFO":
 		if l.mapper.InfoFunc == nil {
 			return nop
 		}
 		return l.mapper.InfoFunc
-	case "WARN ":
+	case "WARN":
 		if l.mapper.WarnFunc == nil {
 			return nop
 		}
@@ -410,10 +884,35 @@ func getDump() []byte {
 	return stacktrace[:length]
 }
 
-// isStreamsSame checks if two streams are the same by comparing file which they refer to
+// isStreamsSame checks if two streams are the same by comparing file which they refer to. Writers exposing their
+// targets with Unwrap() []io.Writer, like MultiWriter, resolved too: s2 is the same if each of its targets is s1
+// or one of s1 targets, so writes to s1 cover s2. io.MultiWriter hides its targets and never matches.
 func isStreamsSame(s1, s2 io.Writer) bool {
-	s1File, outOk := s1.(*os.File)
-	s2File, errOk := s2.(*os.File)
+	s1, s2 = unwrapWriter(s1), unwrapWriter(s2)
+	if m2, ok := s2.(interface{ Unwrap() []io.Writer }); ok {
+		targets := m2.Unwrap()
+		for _, w := range targets {
+			if !isStreamsSame(s1, w) {
+				return false
+			}
+		}
+		return len(targets) > 0
+	}
+	if m1, ok := s1.(interface{ Unwrap() []io.Writer }); ok {
+		for _, w := range m1.Unwrap() {
+			if isStreamsSame(w, s2) {
+				return true
+			}
+		}
+		return false
+	}
+	if r1, ok := s1.(*ReopenFile); ok {
+		if r2, ok := s2.(*ReopenFile); ok && r1.path == r2.path {
+			return true
+		}
+	}
+	s1File, outOk := streamFile(s1)
+	s2File, errOk := streamFile(s2)
 	if outOk && errOk {
 		outStat, err := s1File.Stat()
 		if err != nil {
@@ -425,5 +924,33 @@ func isStreamsSame(s1, s2 io.Writer) bool {
 		}
 		return os.SameFile(outStat, errStat)
 	}
+	if s1 == nil || s2 == nil || reflect.TypeOf(s1) != reflect.TypeOf(s2) || !reflect.TypeOf(s1).Comparable() {
+		return false // comparison of non-comparable writers, i.e. struct with slice passed by value, panics
+	}
 	return s1 == s2
 }
+
+// unwrapWriter returns the underlying writer for wrappers with Unwrap() io.Writer method
+func unwrapWriter(w io.Writer) io.Writer {
+	for i := 0; i < 10; i++ { // limit depth to avoid endless loop on self-referencing wrappers
+		u, ok := w.(interface{ Unwrap() io.Writer })
+		if !ok {
+			return w
+		}
+		w = u.Unwrap()
+	}
+	return w
+}
+
+// streamFile returns the file behind the writer, for *os.File and *ReopenFile
+func streamFile(w io.Writer) (*os.File, bool) {
+	switch v := w.(type) {
+	case *os.File:
+		return v, true
+	case *ReopenFile:
+		v.lock.Lock()
+		defer v.lock.Unlock()
+		return v.file, v.file != nil
+	}
+	return nil, false
+}
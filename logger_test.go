@@ -384,6 +384,35 @@ func TestLoggerWithErrorSameOutputs(t *testing.T) {
 	assert.Equal(t, 1, fatalCalls)
 }
 
+func TestLoggerExitHooks(t *testing.T) {
+	var calls []string
+	rout := bytes.NewBuffer([]byte{})
+	bufOut := &bufferedWriter{dst: rout}
+	var l *Logger
+	l = New(Out(bufOut), Err(bufOut),
+		ExitHook(func() { calls = append(calls, "hook1"); l.Logf("INFO from hook") }),
+		ExitHook(func() { calls = append(calls, "hook2") }),
+	)
+	l.now = func() time.Time { return time.Date(2018, 1, 7, 13, 2, 34, 0, time.Local) }
+	l.fatal = func() { calls = append(calls, "fatal") }
+
+	l.Logf("FATAL oh my, fatal error!")
+	assert.Equal(t, []string{"hook1", "hook2", "fatal"}, calls)
+	assert.Equal(t, "2018/01/07 13:02:34 FATAL oh my, fatal error!\n2018/01/07 13:02:34 INFO  from hook\n", rout.String(),
+		"flushed after hooks")
+}
+
+func TestLoggerExitTimeout(t *testing.T) {
+	fatalCalls := 0
+	rout, rerr := bytes.NewBuffer([]byte{}), bytes.NewBuffer([]byte{})
+	l := New(Out(rout), Err(rerr), ExitTimeout(10*time.Millisecond), ExitHook(func() { time.Sleep(time.Second) }))
+	l.fatal = func() { fatalCalls++ }
+	st := time.Now()
+	l.Logf("PANIC oh my, panic now!")
+	assert.Equal(t, 1, fatalCalls)
+	assert.Less(t, time.Since(st), 500*time.Millisecond)
+}
+
 func TestLoggerConcurrent(t *testing.T) {
 	rout, rerr := bytes.NewBuffer([]byte{}), bytes.NewBuffer([]byte{})
 	l := New(Debug, Out(rout), Err(rerr))
@@ -488,11 +517,11 @@ func TestLoggerNoSpaceLevel(t *testing.T) {
 		args       []interface{}
 		rout, rerr string
 	}{
-		{"INFOsomething 123 %s", []interface{}{"aaa1"}, "2018/01/07 13:02:34.000 INFO  something 123 aaa1\n", ""},
+		{"INFOsomething 123 %s", []interface{}{"aaa1"}, "2018/01/07 13:02:34.000 INFO  INFOsomething 123 aaa1\n", ""},
 		{"[INFO]something 123 %s", []interface{}{"aaa1"}, "2018/01/07 13:02:34.000 INFO  something 123 aaa1\n", ""},
 		{"[INFO]something 123 %s", []interface{}{"aaa1\n"}, "2018/01/07 13:02:34.000 INFO  something 123 aaa1\n", ""},
-		{"WARNsomething 123 %s", []interface{}{"aaa1"}, "2018/01/07 13:02:34.000 WARN  something 123 aaa1\n", ""},
-		{"ERRORsomething 123 %s", []interface{}{"aaa1"}, "2018/01/07 13:02:34.000 ERROR something 123 aaa1\n",
+		{"WARNING: something 123 %s", []interface{}{"aaa1"}, "2018/01/07 13:02:34.000 INFO  WARNING: something 123 aaa1\n", ""},
+		{"ERROR:something 123 %s", []interface{}{"aaa1"}, "2018/01/07 13:02:34.000 ERROR something 123 aaa1\n",
 			"2018/01/07 13:02:34.000 ERROR something 123 aaa1\n"},
 	}
 	rout, rerr := bytes.NewBuffer([]byte{}), bytes.NewBuffer([]byte{})
@@ -594,3 +623,326 @@ func BenchmarkWithDbgAndFormat(b *testing.B) {
 		l.Logf("INFO test test 123 debug message #%d, %v", n, e)
 	}
 }
+
+// bufferedWriter buffers writes until Flush called
+type bufferedWriter struct {
+	buf bytes.Buffer
+	dst *bytes.Buffer
+}
+
+func (w *bufferedWriter) Write(p []byte) (int, error) { return w.buf.Write(p) }
+
+func (w *bufferedWriter) Flush() error {
+	_, err := w.buf.WriteTo(w.dst)
+	return err
+}
+
+func TestParseLevel(t *testing.T) {
+	tbl := []struct {
+		line, level, msg string
+	}{
+		{"INFO something", "INFO", "something"},
+		{"[WARN] something ", "WARN", "something"},
+		{"ERRORsomething", "INFO", "ERRORsomething"},
+		{"WARNING: disk almost full", "INFO", "WARNING: disk almost full"},
+		{"ERROR: something", "ERROR", "something"},
+		{"DEBUG\tsomething", "DEBUG", "something"},
+		{"PANIC", "PANIC", ""},
+		{"[INFO]something", "INFO", "something"},
+		{"[TRACE]", "TRACE", ""},
+		{"something", "INFO", "something"},
+		{" DEBUG something", "INFO", " DEBUG something"},
+		{"[DEBUG something", "INFO", "[DEBUG something"},
+		{"", "INFO", ""},
+	}
+	for _, tt := range tbl {
+		level, msg := ParseLevel(tt.line)
+		assert.Equal(t, tt.level, level, tt.line)
+		assert.Equal(t, tt.msg, msg, tt.line)
+	}
+}
+
+func FuzzParseLevel(f *testing.F) {
+	for _, s := range []string{"INFO msg", "[ERROR] msg", "PANIC", "[WARN", "msg", "", "[]", "\n"} {
+		f.Add(s)
+	}
+	f.Fuzz(func(t *testing.T, line string) {
+		level, msg := ParseLevel(line)
+		known := false
+		for _, lv := range levels {
+			known = known || level == lv
+		}
+		require.True(t, known, "unknown level %q", level)
+		require.LessOrEqual(t, len(msg), len(line))
+
+		prefixed := false
+		for _, lv := range levels {
+			bare := strings.HasPrefix(line, lv) && isLevelDelim(line[len(lv):])
+			prefixed = prefixed || bare || strings.HasPrefix(line, "["+lv+"]")
+			if strings.HasPrefix(line, lv) && !bare { // level without delimiter is a part of the word, never split
+				require.Equal(t, "INFO", level)
+				require.Equal(t, line, msg)
+			}
+		}
+		if !prefixed {
+			require.Equal(t, "INFO", level)
+			require.Equal(t, line, msg)
+			return
+		}
+		require.Equal(t, strings.TrimSpace(msg), msg)
+		require.True(t, strings.Contains(line, msg))
+	})
+}
+
+func TestLoggerLevelBracesPadding(t *testing.T) {
+	rout, rerr := bytes.NewBuffer([]byte{}), bytes.NewBuffer([]byte{})
+	l := New(Trace, Out(rout), Err(rerr), Format(`[{{.Level}}]|{{.LevelBraces}}|{{.Message}}`))
+	for _, lv := range []string{"TRACE", "INFO", "WARN", "ERROR"} {
+		l.Logf(lv + " msg")
+	}
+	assert.Equal(t, "[TRACE]|[TRACE]|msg\n[INFO] |[INFO] |msg\n[WARN] |[WARN] |msg\n[ERROR]|[ERROR]|msg\n", rout.String())
+
+	rout.Reset()
+	l = New(Trace, Out(rout), Err(rerr), LevelBraces, Format(""))
+	l.now = func() time.Time { return time.Date(2018, 1, 7, 13, 2, 34, 0, time.Local) }
+	l.Logf("TRACE msg")
+	l.Logf("WARN msg")
+	assert.Equal(t, "2018/01/07 13:02:34 [TRACE] msg\n2018/01/07 13:02:34 [WARN]  msg\n", rout.String())
+
+	assert.Equal(t, "[ABCDEFG]", l.formatLevelBraces("ABCDEFG"))
+	assert.Equal(t, "AB   ", l.formatLevel("AB"))
+}
+
+func TestLoggerLevelPad(t *testing.T) {
+	tbl := []struct {
+		opts []Option
+		res  string
+	}{
+		{[]Option{}, "INFO |[INFO] |msg\n"},
+		{[]Option{LevelPad(LevelPadRight)}, "INFO |[INFO] |msg\n"},
+		{[]Option{LevelPad(LevelPadLeft)}, " INFO| [INFO]|msg\n"},
+		{[]Option{NoLevelPad}, "INFO|[INFO]|msg\n"},
+	}
+	for i, tt := range tbl {
+		rout := bytes.NewBuffer([]byte{})
+		l := New(append(tt.opts, Out(rout), Format(`{{.Level}}|{{.LevelBraces}}|{{.Message}}`))...)
+		l.Logf("INFO msg")
+		assert.Equal(t, tt.res, rout.String(), "case %d", i)
+	}
+
+	rout := bytes.NewBuffer([]byte{})
+	l := New(Out(rout), NoLevelPad, LevelBraces, Map(Mapper{
+		WarnFunc: func(s string) string { return "<" + s + ">" },
+		TimeFunc: func(s string) string { return s },
+	}))
+	l.now = func() time.Time { return time.Date(2018, 1, 7, 13, 2, 34, 0, time.Local) }
+	l.Logf("WARN msg")
+	assert.Equal(t, "2018/01/07 13:02:34 <[WARN]> <msg>\n", rout.String())
+}
+
+func TestLoggerPrefix(t *testing.T) {
+	rout, rerr := bytes.NewBuffer([]byte{}), bytes.NewBuffer([]byte{})
+	l := New(Out(rout), Err(rerr), Prefix("[worker-3] "))
+	l.now = func() time.Time { return time.Date(2018, 1, 7, 13, 2, 34, 0, time.Local) }
+	l.Logf("WARN something %d", 123)
+	l.Tagged("db").Logf("INFO query")
+	assert.Equal(t, "2018/01/07 13:02:34 WARN  [worker-3] something 123\n2018/01/07 13:02:34 INFO  #db [worker-3] query\n",
+		rout.String())
+
+	rout.Reset()
+	l = New(Out(rout), Err(rerr), Prefix("w1: "), Format(`{{.Level}} {{.Message}}`))
+	l.Logf("ERROR failed")
+	assert.Equal(t, "ERROR w1: failed\n", rout.String())
+	assert.Equal(t, "ERROR w1: failed\n", rerr.String())
+}
+
+func TestLoggerIndent(t *testing.T) {
+	rout, rerr := bytes.NewBuffer([]byte{}), bytes.NewBuffer([]byte{})
+	l := New(Out(rout), Err(rerr), Prefix("> "), Format(`{{.Level}} {{.Message}}`))
+
+	l.Logf("INFO migration started")
+	outdent := l.Indent()
+	l.Logf("INFO step 1")
+	func() {
+		defer l.Indent()()
+		l.Tagged("db").Logf("INFO create table")
+	}()
+	l.Logf("INFO step 2")
+	outdent()
+	outdent() // second call ignored
+	l.Logf("INFO migration completed")
+	l.Outdent() // never below zero
+	l.Logf("INFO done")
+
+	assert.Equal(t, "INFO  > migration started\nINFO    > step 1\nINFO      > #db create table\nINFO    > step 2\n"+
+		"INFO  > migration completed\nINFO  > done\n", rout.String())
+}
+
+func TestLoggerPanicSafeFormatter(t *testing.T) {
+	rout, rerr := bytes.NewBuffer([]byte{}), bytes.NewBuffer([]byte{})
+	l := New(Out(rout), Err(rerr), Map(Mapper{InfoFunc: func(string) string { panic("bad mapper") }}))
+	l.now = func() time.Time { return time.Date(2018, 1, 7, 13, 2, 34, 0, time.Local) }
+
+	l.Logf("INFO message %d", 123)
+	assert.Equal(t, "2018/01/07 13:02:34 INFO  message 123\n2018/01/07 13:02:34 ERROR lgr: formatter panic, bad mapper\n",
+		rout.String())
+
+	rout.Reset()
+	l.Logf("WARN partial mapper without TimeFunc")
+	assert.Equal(t, "2018/01/07 13:02:34 WARN  partial mapper without TimeFunc\n", rout.String())
+}
+
+func TestLoggerTemplateFailure(t *testing.T) {
+	rout, rerr := bytes.NewBuffer([]byte{}), bytes.NewBuffer([]byte{})
+	var errs []error
+	l := New(Out(rout), Err(rerr), Format(`{{.Level}} {{if .Message}}{{.Message.Bad}}{{end}}`),
+		OnError(func(err error) { errs = append(errs, err) }))
+	l.now = func() time.Time { return time.Date(2018, 1, 7, 13, 2, 34, 0, time.Local) }
+
+	l.Logf("WARN message")
+	assert.Equal(t, "2018/01/07 13:02:34 WARN  message\n2018/01/07 13:02:34 ERROR lgr: failed to execute template, "+
+		"template: lgr:1:36: executing \"lgr\" at <.Message.Bad>: can't evaluate field Bad in type string\n", rout.String())
+	require.Len(t, errs, 1)
+	assert.Contains(t, errs[0].Error(), "can't evaluate field Bad")
+	assert.Equal(t, "", rerr.String())
+}
+
+func TestLoggerErrOrder(t *testing.T) {
+	tbl := []struct {
+		order ErrOrdering
+		res   string
+	}{
+		{ErrAfterOut, "out: INFO  info\nout: ERROR failed\nerr: ERROR failed\n"},
+		{ErrBeforeOut, "out: INFO  info\nerr: ERROR failed\nout: ERROR failed\n"},
+		{ErrInsteadOfOut, "out: INFO  info\nerr: ERROR failed\n"},
+	}
+
+	for _, tt := range tbl {
+		t.Run(strconv.Itoa(int(tt.order)), func(t *testing.T) {
+			merged := bytes.NewBuffer([]byte{})
+			out, errw := prefixedWriter{"out: ", merged}, prefixedWriter{"err: ", merged}
+			l := New(Out(out), Err(errw), ErrOrder(tt.order), Format(`{{.Level}} {{.Message}}`))
+			l.Logf("INFO info")
+			l.Logf("ERROR failed")
+			assert.Equal(t, tt.res, merged.String())
+		})
+	}
+}
+
+type prefixedWriter struct {
+	prefix string
+	w      *bytes.Buffer
+}
+
+func (p prefixedWriter) Write(data []byte) (int, error) {
+	return p.w.Write(append([]byte(p.prefix), data...))
+}
+
+func TestLoggerFormatErr(t *testing.T) {
+	rout, rerr := bytes.NewBuffer([]byte{}), bytes.NewBuffer([]byte{})
+	l := New(Out(rout), Err(rerr), FormatErr(`{"level":"{{.LevelRaw}}","msg":{{json .Message}},"line":{{.CallerLine}}}`))
+	l.now = func() time.Time { return time.Date(2018, 1, 7, 13, 2, 34, 0, time.UTC) }
+
+	l.Logf("INFO done")
+	l.Logf("ERROR failed %q", "x")
+	assert.Equal(t, "2018/01/07 13:02:34 INFO  done\n2018/01/07 13:02:34 ERROR failed \"x\"\n", rout.String())
+	assert.Regexp(t, `^\{"level":"ERROR","msg":"failed \\"x\\"","line":\d+\}\n$`, rerr.String())
+	assert.NotContains(t, rerr.String(), `"line":0`, "caller enabled by err format")
+
+	rerr.Reset()
+	l = New(Out(rout), Err(rerr), FormatErr(`{{.Bad`))
+	l.Logf("ERROR failed")
+	assert.Contains(t, rerr.String(), " ERROR failed\n", "invalid err format switched to Short")
+}
+
+func TestLoggerScrub(t *testing.T) {
+	rout, rerr := bytes.NewBuffer([]byte{}), bytes.NewBuffer([]byte{})
+	forgotten := "user-123"
+	scrub := func(msg string) (string, bool) {
+		if strings.Contains(msg, "forget-me") {
+			return "", false
+		}
+		return strings.ReplaceAll(msg, forgotten, "user-*"), true
+	}
+	l := New(Out(rout), Err(rerr), Scrub(scrub), Format(`{{.Level}} {{.Message}}`))
+
+	l.Logf("INFO login %s", forgotten)
+	l.Logf("ERROR failed for %s", forgotten)
+	l.Logf("INFO forget-me record")
+	assert.Equal(t, "INFO  login user-*\nERROR failed for user-*\n", rout.String())
+	assert.Equal(t, "ERROR failed for user-*\n", rerr.String())
+}
+
+func TestLoggerTimeZone(t *testing.T) {
+	rout, rerr := bytes.NewBuffer([]byte{}), bytes.NewBuffer([]byte{})
+	est := time.FixedZone("EST", -5*3600)
+	l := New(Out(rout), Err(rerr), TimeZone(est), TimeZoneErr(time.UTC), Format(`{{.DT.Format "15:04 MST"}} {{.Message}}`))
+	l.now = func() time.Time { return time.Date(2018, 1, 7, 13, 2, 34, 0, time.UTC) }
+
+	l.Logf("ERROR failed")
+	assert.Equal(t, "08:02 EST failed\n", rout.String())
+	assert.Equal(t, "13:02 UTC failed\n", rerr.String())
+}
+
+func TestLoggerInternedLevels(t *testing.T) {
+	for _, pad := range []LevelPadding{LevelPadRight, LevelPadLeft, LevelPadNone} {
+		l := New(LevelPad(pad))
+		assert.Equal(t, 0.0, testing.AllocsPerRun(100, func() { _ = l.formatLevel("INFO") }))
+		assert.Equal(t, 0.0, testing.AllocsPerRun(100, func() { _ = l.formatLevelBraces("WARN") }))
+	}
+	assert.Equal(t, " INFO", New(LevelPad(LevelPadLeft)).formatLevel("INFO"))
+	assert.Equal(t, "[WARN] ", New().formatLevelBraces("WARN"))
+	assert.Equal(t, "[XYZ]  ", New().formatLevelBraces("XYZ"), "unknown level not interned")
+
+	pc := callerPC(-3) // the test function itself, calling callerPC directly
+	ci := callerFromPC(pc)
+	assert.Equal(t, "lgr.TestLoggerInternedLevels", ci.FuncName)
+	assert.Equal(t, 0.0, testing.AllocsPerRun(100, func() { _ = callerFromPC(pc) }), "cached caller info")
+}
+
+func TestLoggerEOL(t *testing.T) {
+	tbl := []struct {
+		opts []Option
+		res  string
+	}{
+		{nil, "2018/01/07 13:02:34 INFO  first\n2018/01/07 13:02:34 ERROR second\n"},
+		{[]Option{EOL("\r\n")}, "2018/01/07 13:02:34 INFO  first\r\n2018/01/07 13:02:34 ERROR second\r\n"},
+		{[]Option{EOL("\x00"), Format(Short)}, "2018/01/07 13:02:34 INFO  first\x002018/01/07 13:02:34 ERROR second\x00"},
+	}
+
+	for i, tt := range tbl {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			rout := bytes.NewBuffer([]byte{})
+			l := New(append([]Option{Out(rout), Err(rout)}, tt.opts...)...)
+			l.now = func() time.Time { return time.Date(2018, 1, 7, 13, 2, 34, 0, time.Local) }
+			l.Logf("INFO first\n")
+			l.Logf("ERROR second")
+			assert.Equal(t, tt.res, rout.String())
+		})
+	}
+
+	rout := bytes.NewBuffer([]byte{})
+	l := New(Out(rout), EOL("\r\n"), Format(`{{if .Message}}{{.Message.Bad}}{{end}}`))
+	l.Logf("INFO first")
+	assert.Equal(t, 2, strings.Count(rout.String(), "\r\n"), "fallback on template failure")
+}
+
+func TestLoggerInvalidTemplateReported(t *testing.T) {
+	rout, rerr := bytes.NewBuffer([]byte{}), bytes.NewBuffer([]byte{})
+	l := New(Out(rout), Err(rerr), Format(`{{.Message`))
+	l.Logf("INFO something")
+	assert.Contains(t, rout.String(), "INFO  something\n", "switched to Short")
+	assert.Contains(t, rerr.String(), "lgr: invalid template {{.Message,")
+	assert.Contains(t, rerr.String(), "switched to "+Short+"\n")
+
+	var errs []error
+	rout, rerr = bytes.NewBuffer([]byte{}), bytes.NewBuffer([]byte{})
+	_ = New(Out(rout), Err(rerr), Format(`{{.BadThing}}`), FormatErr(`{{.Message`), OnError(func(err error) {
+		errs = append(errs, err)
+	}))
+	require.Equal(t, 2, len(errs))
+	assert.Contains(t, errs[0].Error(), "lgr: failed to execute template {{.BadThing}}")
+	assert.Contains(t, errs[1].Error(), "lgr: invalid template {{.Message")
+	assert.Empty(t, rout.String())
+	assert.Empty(t, rerr.String())
+}
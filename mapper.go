@@ -1,5 +1,7 @@
 package lgr
 
+import "strings"
+
 // Mapper defines optional functions to change elements of the logged message for each part, based on levels.
 // Only some mapFunc can be defined, by default does nothing. Can be used to alter the output, for example making some
 // part of the output colorful.
@@ -10,19 +12,51 @@ type Mapper struct {
 	InfoFunc    mapFunc // message mapper on INFO level
 	DebugFunc   mapFunc // message mapper on DEBUG level
 
-	CallerFunc mapFunc // caller mapper, all levels
-	TimeFunc   mapFunc // time mapper, all levels
+	CallerFunc   mapFunc // caller mapper, all levels
+	TimeFunc     mapFunc // time mapper, all levels
+	LevelMapFunc mapFunc // level mapper, all levels, applied before level-specific mappers
 }
 
 type mapFunc func(string) string
 
 // nopMapper is a default, doing nothing
 var nopMapper = Mapper{
-	MessageFunc: func(s string) string { return s },
-	ErrorFunc:   func(s string) string { return s },
-	WarnFunc:    func(s string) string { return s },
-	InfoFunc:    func(s string) string { return s },
-	DebugFunc:   func(s string) string { return s },
-	CallerFunc:  func(s string) string { return s },
-	TimeFunc:    func(s string) string { return s },
+	MessageFunc:  func(s string) string { return s },
+	ErrorFunc:    func(s string) string { return s },
+	WarnFunc:     func(s string) string { return s },
+	InfoFunc:     func(s string) string { return s },
+	DebugFunc:    func(s string) string { return s },
+	CallerFunc:   func(s string) string { return s },
+	TimeFunc:     func(s string) string { return s },
+	LevelMapFunc: func(s string) string { return s },
+}
+
+// DefaultSymbols used by SymbolsMapper for levels without custom symbol
+var DefaultSymbols = map[string]string{
+	"TRACE": "·",
+	"DEBUG": "·",
+	"INFO":  "ℹ",
+	"WARN":  "⚠",
+	"ERROR": "✖",
+	"PANIC": "✖",
+	"FATAL": "✖",
+}
+
+// SymbolsMapper makes Mapper prepending a symbol to the level, i.e. "⚠ WARN", for human-friendly CLI output.
+// Symbols map level to symbol, i.e. {"INFO": "✔"}, DefaultSymbols used for missing levels.
+// The result can be extended with other mapper functions, i.e. for colors.
+func SymbolsMapper(symbols map[string]string) Mapper {
+	res := nopMapper
+	res.LevelMapFunc = func(s string) string {
+		lv := strings.Trim(s, "[] ")
+		sym, ok := symbols[lv]
+		if !ok {
+			sym = DefaultSymbols[lv]
+		}
+		if sym == "" {
+			return s
+		}
+		return sym + " " + s
+	}
+	return res
 }
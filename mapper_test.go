@@ -0,0 +1,43 @@
+package lgr
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSymbolsMapper(t *testing.T) {
+	rout, rerr := bytes.NewBuffer([]byte{}), bytes.NewBuffer([]byte{})
+	l := New(Out(rout), Err(rerr), Debug, Map(SymbolsMapper(map[string]string{"INFO": "✔", "DEBUG": ""})))
+	l.now = func() time.Time { return time.Date(2018, 1, 7, 13, 2, 34, 0, time.Local) }
+
+	l.Logf("INFO done")
+	l.Logf("WARN careful")
+	l.Logf("ERROR failed")
+	l.Logf("DEBUG details")
+	assert.Equal(t, "2018/01/07 13:02:34 ✔ INFO  done\n2018/01/07 13:02:34 ⚠ WARN  careful\n"+
+		"2018/01/07 13:02:34 ✖ ERROR failed\n2018/01/07 13:02:34 DEBUG details\n", rout.String())
+
+	rout.Reset()
+	m := SymbolsMapper(nil)
+	m.WarnFunc = func(s string) string { return "<" + s + ">" }
+	l = New(Out(rout), Err(rerr), LevelBraces, Map(m))
+	l.now = func() time.Time { return time.Date(2018, 1, 7, 13, 2, 34, 0, time.Local) }
+	l.Logf("WARN careful")
+	l.Logf("INFO done")
+	assert.Equal(t, "2018/01/07 13:02:34 <⚠ [WARN] > <careful>\n2018/01/07 13:02:34 ℹ [INFO]  done\n", rout.String())
+}
+
+func TestMapErr(t *testing.T) {
+	rout, rerr := bytes.NewBuffer([]byte{}), bytes.NewBuffer([]byte{})
+	red := Mapper{ErrorFunc: func(s string) string { return "\033[31m" + s + "\033[0m" }}
+	l := New(Out(rout), Err(rerr), Secret("pass"), MapErr(red))
+	l.now = func() time.Time { return time.Date(2018, 1, 7, 13, 2, 34, 0, time.Local) }
+
+	l.Logf("INFO done")
+	l.Logf("ERROR failed with pass")
+	assert.Equal(t, "2018/01/07 13:02:34 INFO  done\n2018/01/07 13:02:34 ERROR failed with ******\n", rout.String())
+	assert.Equal(t, "2018/01/07 13:02:34 \033[31mERROR\033[0m \033[31mfailed with ******\033[0m\n", rerr.String())
+}
@@ -0,0 +1,63 @@
+package lgr
+
+import (
+	"strconv"
+	"sync/atomic"
+	"time"
+)
+
+var opCounter uint64 // ids of top-level operations
+
+// Op is a logged operation made by Begin, with start and end records and nested operations.
+// Records of the operation prefixed with its id, i.e. "[op 3.1]" for the first nested operation of the third one.
+type Op struct {
+	base     *Logger // logger of the operation's parent, without op prefix
+	l        *Logger // logger with op prefix
+	name     string
+	id       string
+	start    time.Time
+	children uint64 // atomic, counter of nested operations
+	ended    int32  // atomic, set by the first End call
+}
+
+// Begin starts operation with INFO record, i.e. "[op 3] begin sync users". Designed for poor-man's tracing,
+// like op := l.Begin("sync users"); defer func() { op.End(err) }()
+func (l *Logger) Begin(name string) *Op {
+	op := newOp(l, name, strconv.FormatUint(atomic.AddUint64(&opCounter, 1), 10))
+	op.l.logf("INFO begin %s", name)
+	return op
+}
+
+// Begin starts nested operation, with id made from the parent's one, i.e. "3.1"
+func (o *Op) Begin(name string) *Op {
+	op := newOp(o.base, name, o.id+"."+strconv.FormatUint(atomic.AddUint64(&o.children, 1), 10))
+	op.l.logf("INFO begin %s", name)
+	return op
+}
+
+// Logf logs message of the operation, with the operation's id prefix
+func (o *Op) Logf(format string, args ...interface{}) {
+	o.l.logf(format, args...)
+}
+
+// End finishes operation with INFO record with duration, i.e. "[op 3] end sync users, 12ms",
+// or with ERROR record if err is not nil. Only the first call logs.
+func (o *Op) End(err error) {
+	if !atomic.CompareAndSwapInt32(&o.ended, 0, 1) {
+		return
+	}
+	if err != nil {
+		o.l.logf("ERROR end %s, %v, failed: %v", o.name, o.l.now().Sub(o.start), err)
+		return
+	}
+	o.l.logf("INFO end %s, %v", o.name, o.l.now().Sub(o.start))
+}
+
+// ID returns id of the operation, i.e. "3.1"
+func (o *Op) ID() string { return o.id }
+
+func newOp(l *Logger, name, id string) *Op {
+	res := &Op{base: l, l: l.clone(), name: name, id: id, start: l.now()}
+	res.l.prefix = l.prefix + "[op " + id + "] "
+	return res
+}
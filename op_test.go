@@ -0,0 +1,40 @@
+package lgr
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoggerBeginEnd(t *testing.T) {
+	rout, rerr := bytes.NewBuffer([]byte{}), bytes.NewBuffer([]byte{})
+	l := New(Out(rout), Err(rerr), Format(`{{.Level}} {{.CallerFunc}} {{.Message}}`))
+	ts := time.Date(2018, 1, 7, 13, 2, 34, 0, time.UTC)
+	l.now = func() time.Time { return ts }
+
+	op := l.Begin("sync users")
+	id := op.ID()
+	op.Logf("INFO fetched %d users", 10)
+	nested := op.Begin("load page")
+	ts = ts.Add(5 * time.Millisecond)
+	nested.End(nil)
+	op.Begin("save").End(errors.New("boom"))
+	ts = ts.Add(7 * time.Millisecond)
+	op.End(nil)
+	op.End(errors.New("ignored"))
+
+	assert.Equal(t, "INFO  lgr.TestLoggerBeginEnd [op "+id+"] begin sync users\n"+
+		"INFO  lgr.TestLoggerBeginEnd [op "+id+"] fetched 10 users\n"+
+		"INFO  lgr.TestLoggerBeginEnd [op "+id+".1] begin load page\n"+
+		"INFO  lgr.TestLoggerBeginEnd [op "+id+".1] end load page, 5ms\n"+
+		"INFO  lgr.TestLoggerBeginEnd [op "+id+".2] begin save\n"+
+		"ERROR lgr.TestLoggerBeginEnd [op "+id+".2] end save, 0s, failed: boom\n"+
+		"INFO  lgr.TestLoggerBeginEnd [op "+id+"] end sync users, 12ms\n", rout.String())
+	assert.Equal(t, "ERROR lgr.TestLoggerBeginEnd [op "+id+".2] end save, 0s, failed: boom\n", rerr.String())
+
+	next := l.Begin("next")
+	assert.NotEqual(t, id, next.ID(), "unique ids of top-level operations")
+}
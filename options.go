@@ -2,7 +2,9 @@ package lgr
 
 import (
 	"io"
+	"os"
 	"strings"
+	"time"
 )
 
 // Option func type
@@ -24,13 +26,38 @@ func Err(w io.Writer) Option {
 
 // Debug turn on dbg mode
 func Debug(l *Logger) {
-	l.dbg = true
+	l.lowerLevel("DEBUG")
 }
 
 // Trace turn on trace + dbg mode
 func Trace(l *Logger) {
-	l.dbg = true
-	l.trace = true
+	l.lowerLevel("TRACE")
+}
+
+// MinLevel sets the lowest reported level, i.e. MinLevel("WARN") to drop INFO as well. Combined with Debug and Trace,
+// the lowest of them used, so MinLevel("WARN") with Debug reports DEBUG. ERROR and higher records still mirrored to
+// err writer, and FATAL and PANIC always reported. Unknown level ignored. The level can be changed later with SetLevel.
+func MinLevel(level string) Option {
+	return func(l *Logger) {
+		l.lowerLevel(strings.ToUpper(strings.TrimSpace(level)))
+	}
+}
+
+// PackageLevel sets the lowest reported level per package, i.e. PackageLevel(map[string]string{"internal/db": "DEBUG",
+// "vendorlib": "WARN"}), overriding logger's level for callers in these packages. Package matches whole elements
+// of the caller's import path, subpackages included, and the most specific matching package used. Entries with unknown
+// levels ignored. Note: caller evaluated for each record, even filtered, if set.
+func PackageLevel(pkgLevels map[string]string) Option {
+	return func(l *Logger) {
+		l.pkgLevels = make(map[string]int, len(pkgLevels))
+		for pkg, lv := range pkgLevels {
+			idx := levelIndex(strings.ToUpper(strings.TrimSpace(lv)))
+			if pkg = strings.Trim(pkg, "/"); pkg == "" || idx < 0 {
+				continue
+			}
+			l.pkgLevels[pkg] = idx
+		}
+	}
 }
 
 // CallerDepth sets number of stack frame skipped for caller reporting, 0 by default
@@ -72,6 +99,67 @@ func Msec(l *Logger) {
 	l.msec = true
 }
 
+// FormatErr sets layout for ERROR, FATAL and PANIC records mirrored to err writer, i.e. FormatErr(lgr.GCP)
+// for structured errors consumed by machines while humans read the output writer.
+// Without FormatErr the err writer gets the same output as out.
+func FormatErr(f string) Option {
+	return func(l *Logger) {
+		l.formatErr = f
+	}
+}
+
+// TimeZone sets time zone of records, i.e. TimeZone(time.UTC). Local time zone used by default.
+func TimeZone(loc *time.Location) Option {
+	return func(l *Logger) {
+		l.tz = loc
+	}
+}
+
+// TimeZoneErr sets time zone of ERROR, FATAL and PANIC records mirrored to err writer, i.e. UTC for structured errors
+// set with FormatErr while the output writer shows local time. Without TimeZoneErr the time zone of out used.
+func TimeZoneErr(loc *time.Location) Option {
+	return func(l *Logger) {
+		l.tzErr = loc
+	}
+}
+
+// Prefix sets static prefix added to each message, i.e. Prefix("[worker-3] ").
+// Useful to distinguish multiple logger instances writing to the same output.
+func Prefix(p string) Option {
+	return func(l *Logger) {
+		l.prefix = p
+	}
+}
+
+// WriteTimeout limits time of each write to the output writers, so a hung NFS mount or blocked pipe never freezes
+// goroutines logging to it. Uses SetWriteDeadline if supported by the writer, i.e. for pipes and sockets, otherwise
+// writes in goroutine and stops waiting on timeout. Writes dropped while the timed out write is still blocked.
+func WriteTimeout(d time.Duration) Option {
+	return func(l *Logger) {
+		l.writeTimeout = d
+	}
+}
+
+// EOL sets record terminator, "\n" by default. I.e. EOL("\r\n") for files consumed by Windows tools,
+// or EOL("\x00") for consumers of NUL-separated records, like xargs -0.
+func EOL(eol string) Option {
+	return func(l *Logger) {
+		l.eol = eol
+	}
+}
+
+// SoftWrap breaks long messages written to terminal at width, with continuation lines indented under the message
+// start. Width 0 means the terminal width, detected once by New. Writers other than terminal, like files and pipes,
+// get messages as is.
+func SoftWrap(width int) Option {
+	return func(l *Logger) {
+		l.wrapWidth = width
+		if width <= 0 {
+			l.wrapWidth = -1
+		}
+	}
+}
+
 // Secret sets list of substring to be hidden, i.e. replaced by "******"
 // Useful to prevent passwords or other sensitive tokens to be logged.
 func Secret(vals ...string) Option {
@@ -85,6 +173,16 @@ func Secret(vals ...string) Option {
 	}
 }
 
+// Scrub sets function applied to each message before it reaches any writer, including forwarding to slog and status line.
+// The function returns scrubbed message, i.e. with user identifiers removed, and false to drop the message completely.
+// Values of fields added by With, WithFields and Logw scrubbed the same way, formatted with fmt.Sprint, and the record
+// dropped if any value dropped. Field keys are not scrubbed. Unlike Secret, allows data-minimization rules defined by code.
+func Scrub(fn func(msg string) (res string, keep bool)) Option {
+	return func(l *Logger) {
+		l.scrub = fn
+	}
+}
+
 // Map sets mapper functions to change elements of the logged message based on levels.
 func Map(m Mapper) Option {
 	return func(l *Logger) {
@@ -92,7 +190,154 @@ func Map(m Mapper) Option {
 	}
 }
 
+// MapErr sets mapper functions for err writer, i.e. colors for interactive stderr with plain stdout redirected
+// to a file. Without MapErr the err writer gets the same output as out.
+func MapErr(m Mapper) Option {
+	return func(l *Logger) {
+		l.mapErr = &m
+	}
+}
+
 // StackTraceOnError turns on stack trace for ERROR level.
 func StackTraceOnError(l *Logger) {
 	l.errorDump = true
 }
+
+// CrashDir sets directory for crash reports. On PANIC and FATAL levels a self-contained report with the record,
+// environment summary and full stack is written to this directory before exit.
+func CrashDir(dir string) Option {
+	return func(l *Logger) {
+		l.crashDir = dir
+	}
+}
+
+// ExitHook adds a function called on FATAL and PANIC levels before exit. Hooks called in the order of registration,
+// after the record is written and before writers flushed.
+func ExitHook(fn func()) Option {
+	return func(l *Logger) {
+		l.exitHooks = append(l.exitHooks, fn)
+	}
+}
+
+// ExitTimeout sets max time for exit hooks and writers flush on FATAL and PANIC levels, 1s by default.
+func ExitTimeout(d time.Duration) Option {
+	return func(l *Logger) {
+		l.exitTimeout = d
+	}
+}
+
+// MuteTags drops messages with any of given tags, set by Tagged or inline #tag.
+func MuteTags(tags ...string) Option {
+	return func(l *Logger) {
+		if l.mutedTags == nil {
+			l.mutedTags = map[string]bool{}
+		}
+		for _, t := range tags {
+			l.mutedTags[strings.TrimPrefix(t, "#")] = true
+		}
+	}
+}
+
+// TagOut sets output writer for messages with given tag, used instead of Out writer.
+// ERROR and higher levels are still sent to Err writer as well.
+func TagOut(tag string, w io.Writer) Option {
+	return func(l *Logger) {
+		if l.tagRoutes == nil {
+			l.tagRoutes = map[string]tagRoute{}
+		}
+		l.tagRoutes[strings.TrimPrefix(tag, "#")] = tagRoute{out: w}
+	}
+}
+
+// SampleTags sets sampling rates for tags, i.e. {"db": 100, "auth": 1} passes 1 of 100 messages tagged with "db"
+// and all messages tagged with "auth". For a message with multiple sampled tags the lowest rate used.
+func SampleTags(rates map[string]int) Option {
+	return func(l *Logger) {
+		if l.tagSampling == nil {
+			l.tagSampling = map[string]*tagSample{}
+		}
+		for t, r := range rates {
+			if r < 1 {
+				r = 1
+			}
+			l.tagSampling[strings.TrimPrefix(t, "#")] = &tagSample{rate: uint64(r)}
+		}
+	}
+}
+
+// Escalate sets escalation policy for repeated WARN and ERROR messages, see Escalation for details.
+func Escalate(e Escalation) Option {
+	return func(l *Logger) {
+		l.escalator = newEscalator(e)
+	}
+}
+
+// LevelPadding defines alignment of levels shorter than 5 chars, i.e. INFO and WARN
+type LevelPadding int
+
+// enum of all level paddings
+const (
+	LevelPadRight LevelPadding = iota // "INFO ", default
+	LevelPadLeft                      // " INFO"
+	LevelPadNone                      // "INFO", exact token for machine-readable formats
+)
+
+// LevelPad sets alignment of short levels, applied to {{.Level}}, {{.LevelBraces}} and LevelBraces option.
+func LevelPad(p LevelPadding) Option {
+	return func(l *Logger) {
+		l.levelPad = p
+	}
+}
+
+// NoLevelPad turns off alignment of short levels, same as LevelPad(LevelPadNone)
+func NoLevelPad(l *Logger) {
+	l.levelPad = LevelPadNone
+}
+
+// BuildBanner logs INFO record with build info (see BuildInfoFields) on logger creation
+func BuildBanner(l *Logger) {
+	l.buildBanner = true
+}
+
+// OnError sets func called on internal errors, like template execution failure or panic in mapper.
+// The record written with the Short layout in such case, followed by the internal ERROR record.
+// Invalid Format and FormatErr switched to Short on creation and reported as well, to err writer without OnError.
+func OnError(fn func(err error)) Option {
+	return func(l *Logger) {
+		l.onError = fn
+	}
+}
+
+// ErrOrdering defines how ERROR, FATAL and PANIC records mirrored to the error writer
+type ErrOrdering int
+
+// enum of all err orderings
+const (
+	ErrAfterOut     ErrOrdering = iota // written to out, then to err, default
+	ErrBeforeOut                       // written to err, then to out
+	ErrInsteadOfOut                    // written to err only
+)
+
+// ErrOrder sets how ERROR, FATAL and PANIC records mirrored to the error writer. ErrBeforeOut keeps the order
+// deterministic for runtimes merging streams and reading err first, ErrInsteadOfOut avoids duplicates completely.
+// Has no effect if out and err are the same stream.
+func ErrOrder(o ErrOrdering) Option {
+	return func(l *Logger) {
+		l.errOrder = o
+	}
+}
+
+// ClockSkewWarn turns on detection of wall clock jumping backwards between records, i.e. by NTP or VM snapshot restore.
+// WARN record with the skew written before the first record after the jump, explaining out-of-order timestamps.
+// Jumps shorter than 250ms ignored.
+func ClockSkewWarn(l *Logger) {
+	l.clock = newClockMonitor()
+}
+
+// CI sets GitHubActions format if running in GitHub Actions, i.e. GITHUB_ACTIONS env is "true", so WARN and ERROR
+// records become annotations. Does nothing otherwise. Format set after CI overrides it.
+func CI(l *Logger) {
+	if os.Getenv("GITHUB_ACTIONS") == "true" {
+		l.format = GitHubActions
+	}
+}
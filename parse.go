@@ -0,0 +1,75 @@
+package lgr
+
+import (
+	"errors"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Record is a parsed log line, see ParseRecord
+type Record struct {
+	DT         time.Time
+	Level      string
+	Message    string
+	CallerPkg  string
+	CallerFile string
+	CallerFunc string
+	CallerLine int
+	Tags       []string
+}
+
+var reRecord = regexp.MustCompile(`^(\d{4}/\d{2}/\d{2} \d{2}:\d{2}:\d{2}(?:\.\d{3})?) ` +
+	`(?:(TRACE|DEBUG|INFO|WARN|ERROR|PANIC|FATAL)|\[(TRACE|DEBUG|INFO|WARN|ERROR|PANIC|FATAL)\]) +(.*)$`)
+
+// ParseRecord parses a line produced by the standard templates (Short, WithMsec, WithPkg, ShortDebug, FuncDebug and
+// FullDebug) or by individual formatting options, back to Record. Time parsed in the local time zone, as written.
+// Caller part is recognized by (...) or {...} following the level: "file:line" elements set CallerFile and CallerLine,
+// elements with dot set CallerFunc and the rest set CallerPkg. Note: a message starting with ( or { is ambiguous
+// and treated as a caller part.
+func ParseRecord(line string) (Record, error) {
+	line = strings.TrimSuffix(line, "\n")
+	m := reRecord.FindStringSubmatch(line)
+	if m == nil {
+		return Record{}, errors.New("line doesn't match lgr format")
+	}
+
+	layout := "2006/01/02 15:04:05"
+	if len(m[1]) > len(layout) {
+		layout += ".000"
+	}
+	dt, err := time.ParseInLocation(layout, m[1], time.Local)
+	if err != nil {
+		return Record{}, err
+	}
+
+	res := Record{DT: dt, Level: m[2] + m[3]}
+	msg := m[4]
+	if len(msg) > 0 && (msg[0] == '(' || msg[0] == '{') {
+		closing := map[byte]string{'(': ")", '{': "}"}[msg[0]]
+		if end := strings.Index(msg, closing); end > 0 {
+			res.parseCaller(msg[1:end])
+			msg = strings.TrimPrefix(msg[end+1:], " ")
+		}
+	}
+	res.Message, res.Tags = parseTags(msg, nil)
+	return res, nil
+}
+
+// parseCaller sets caller fields from space separated caller elements
+func (r *Record) parseCaller(caller string) {
+	for _, elem := range strings.Fields(caller) {
+		if idx := strings.LastIndex(elem, ":"); idx > 0 {
+			if line, err := strconv.Atoi(elem[idx+1:]); err == nil {
+				r.CallerFile, r.CallerLine = elem[:idx], line
+				continue
+			}
+		}
+		if strings.Contains(elem, ".") {
+			r.CallerFunc = elem
+			continue
+		}
+		r.CallerPkg = elem
+	}
+}
@@ -0,0 +1,58 @@
+package lgr
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseRecord(t *testing.T) {
+	ts := time.Date(2018, 1, 7, 13, 2, 34, 123000000, time.Local)
+	tbl := []struct {
+		opts []Option
+		line string
+		rec  Record
+	}{
+		{[]Option{Format(Short)}, "WARN something 123",
+			Record{DT: ts.Truncate(time.Second), Level: "WARN", Message: "something 123"}},
+		{[]Option{Format(WithMsec)}, "ERROR something (123)",
+			Record{DT: ts, Level: "ERROR", Message: "something (123)"}},
+		{[]Option{Format(WithPkg)}, "DEBUG something",
+			Record{DT: ts, Level: "DEBUG", Message: "something", CallerPkg: "lgr"}},
+		{[]Option{Format(ShortDebug)}, "INFO something",
+			Record{DT: ts, Level: "INFO", Message: "something", CallerFile: "lgr/parse_test.go", CallerLine: 44}},
+		{[]Option{Format(FuncDebug)}, "INFO #db something", // no tags in template, kept in message
+			Record{DT: ts, Level: "INFO", Message: "something", CallerFunc: "lgr.TestParseRecord", Tags: []string{"db"}}},
+		{[]Option{Format(FullDebug)}, "INFO something",
+			Record{DT: ts, Level: "INFO", Message: "something", CallerFile: "lgr/parse_test.go", CallerLine: 44,
+				CallerFunc: "lgr.TestParseRecord"}},
+		{[]Option{Msec, LevelBraces, CallerFile, CallerFunc, CallerPkg}, "INFO something",
+			Record{DT: ts, Level: "INFO", Message: "something", CallerFile: "lgr/parse_test.go", CallerLine: 44,
+				CallerFunc: "lgr.TestParseRecord", CallerPkg: "lgr"}},
+		{[]Option{LevelBraces, CallerPkg}, "TRACE #db #slow something {not caller}",
+			Record{DT: ts.Truncate(time.Second), Level: "TRACE", Message: "something {not caller}", CallerPkg: "lgr",
+				Tags: []string{"db", "slow"}}},
+	}
+
+	for _, tt := range tbl {
+		buf := bytes.NewBuffer([]byte{})
+		l := New(append([]Option{Out(buf), Err(buf), Trace}, tt.opts...)...)
+		l.now = func() time.Time { return ts }
+		l.Logf(tt.line)
+		rec, err := ParseRecord(buf.String())
+		require.NoError(t, err, buf.String())
+		assert.Equal(t, tt.rec, rec, buf.String())
+	}
+}
+
+func TestParseRecordFailed(t *testing.T) {
+	_, err := ParseRecord("something")
+	assert.Error(t, err)
+	_, err = ParseRecord("2018/01/07 13:02:34 BLAH something")
+	assert.Error(t, err)
+	_, err = ParseRecord("2018/13/07 13:02:34 INFO something")
+	assert.Error(t, err)
+}
@@ -0,0 +1,158 @@
+//go:build go1.21
+
+package lgr
+
+import (
+	"context"
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SlogHandler makes slog.Handler writing records with the logger. Levels mapped to lgr levels, with everything
+// below slog.LevelDebug reported as TRACE and everything above slog.LevelError as ERROR. Attributes appended
+// to the message as key=value pairs, with group names as key prefixes, i.e. "req.id=123".
+// The caller reported from the record, so caller info options work for slog calls too.
+func SlogHandler(l *Logger) slog.Handler {
+	res := l.clone()
+	res.forward = nil // handler always writes, even for the logger handing messages off to slog
+	return &slogHandler{l: res}
+}
+
+// SetAsSlogDefault installs the logger as slog's default, with slog.SetDefault. Note: slog.SetDefault also
+// redirects the standard log package to the handler.
+func SetAsSlogDefault(l *Logger) {
+	slog.SetDefault(slog.New(SlogHandler(l)))
+}
+
+// UseSlogDefault makes the default logger, used by Printf, Print and Fatalf, hand messages off to slog.Default().
+// Level filtering and output done by the slog's handler, lgr levels mapped to slog levels with TRACE reported as
+// slog.LevelDebug-4 and FATAL and PANIC as slog.LevelError. FATAL and PANIC still exit after the hand-off.
+// Call Setup to get the default logger writing on its own again.
+func UseSlogDefault() {
+	l := New()
+	l.forward = func(lv, msg string, pc uintptr) {
+		ctx, level := context.Background(), slogLevel(lv)
+		h := slog.Default().Handler()
+		if !h.Enabled(ctx, level) {
+			return
+		}
+		_ = h.Handle(ctx, slog.NewRecord(time.Now(), level, msg, pc))
+	}
+	def = l
+}
+
+type slogHandler struct {
+	l     *Logger
+	attrs string // formatted attributes added by WithAttrs
+	group string // key prefix of the current group, i.e. "req."
+}
+
+// Enabled reports whether the logger writes records at the level. Level override set with ContextWithLevel
+// takes precedence over the logger's own debug and trace options.
+func (h *slogHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	if h.l.noop {
+		return false
+	}
+	if lv, ok := LevelFromContext(ctx); ok {
+		return level >= slogLevel(lv)
+	}
+	return h.l.enabled(lgrLevel(level))
+}
+
+// Handle writes the record with attributes appended to the message
+func (h *slogHandler) Handle(ctx context.Context, r slog.Record) error {
+	if h.l.noop {
+		return nil
+	}
+	l := h.l
+	if lv, ok := LevelFromContext(ctx); ok { // request-scoped level, debug and trace allowed by the context only
+		c := *h.l
+		c.level = newLevel(lgrLevel(slogLevel(lv))) // always a known level
+		l = &c
+	}
+	msg := strings.Builder{}
+	msg.WriteString(r.Message)
+	msg.WriteString(h.attrs)
+	r.Attrs(func(a slog.Attr) bool {
+		writeSlogAttr(&msg, h.group, a)
+		return true
+	})
+	l.log(lgrLevel(r.Level), msg.String(), r.Message, r.PC)
+	return nil
+}
+
+// WithAttrs makes handler adding attributes to each record
+func (h *slogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	res := *h
+	b := strings.Builder{}
+	b.WriteString(h.attrs)
+	for _, a := range attrs {
+		writeSlogAttr(&b, h.group, a)
+	}
+	res.attrs = b.String()
+	return &res
+}
+
+// WithGroup makes handler qualifying keys of the following attributes with the group name
+func (h *slogHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	res := *h
+	res.group = h.group + name + "."
+	return &res
+}
+
+// writeSlogAttr writes " key=value", groups flattened to prefixed keys and empty attributes ignored
+func writeSlogAttr(b *strings.Builder, prefix string, a slog.Attr) {
+	a.Value = a.Value.Resolve()
+	if a.Equal(slog.Attr{}) {
+		return
+	}
+	if a.Value.Kind() == slog.KindGroup {
+		if a.Key != "" {
+			prefix += a.Key + "."
+		}
+		for _, ga := range a.Value.Group() {
+			writeSlogAttr(b, prefix, ga)
+		}
+		return
+	}
+	val := a.Value.String()
+	if val == "" || strings.ContainsAny(val, " \t\n\"=") {
+		val = strconv.Quote(val)
+	}
+	b.WriteString(" " + prefix + a.Key + "=" + val)
+}
+
+// lgrLevel maps slog level to lgr level, never to FATAL or PANIC
+func lgrLevel(level slog.Level) string {
+	switch {
+	case level < slog.LevelDebug:
+		return "TRACE"
+	case level < slog.LevelInfo:
+		return "DEBUG"
+	case level < slog.LevelWarn:
+		return "INFO"
+	case level < slog.LevelError:
+		return "WARN"
+	}
+	return "ERROR"
+}
+
+// slogLevel maps lgr level to slog level
+func slogLevel(lv string) slog.Level {
+	switch lv {
+	case "TRACE":
+		return slog.LevelDebug - 4
+	case "DEBUG":
+		return slog.LevelDebug
+	case "WARN":
+		return slog.LevelWarn
+	case "ERROR", "FATAL", "PANIC":
+		return slog.LevelError
+	}
+	return slog.LevelInfo
+}
@@ -0,0 +1,121 @@
+//go:build go1.21
+
+package lgr
+
+import (
+	"bytes"
+	"context"
+	"log"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSlogHandler(t *testing.T) {
+	tbl := []struct {
+		name string
+		fn   func(l *slog.Logger)
+		res  string
+	}{
+		{"info", func(l *slog.Logger) { l.Info("message") }, "INFO  message\n"},
+		{"attrs", func(l *slog.Logger) { l.Warn("message", "k", 1, "s", "a b") }, "WARN  message k=1 s=\"a b\"\n"},
+		{"error", func(l *slog.Logger) { l.Error("failed", "err", "oops") }, "ERROR failed err=oops\n"},
+		{"above error", func(l *slog.Logger) { l.Log(context.Background(), slog.LevelError+4, "failed") }, "ERROR failed\n"},
+		{"debug", func(l *slog.Logger) { l.Debug("message") }, "DEBUG message\n"},
+		{"trace filtered", func(l *slog.Logger) { l.Log(context.Background(), slog.LevelDebug-4, "message") }, ""},
+		{"with attrs", func(l *slog.Logger) { l.With("id", "abc").Info("message", "k", "v") }, "INFO  message id=abc k=v\n"},
+		{"group", func(l *slog.Logger) { l.WithGroup("req").With("id", 1).Info("message", slog.Group("u", "name", "joe")) },
+			"INFO  message req.id=1 req.u.name=joe\n"},
+		{"empty value", func(l *slog.Logger) { l.Info("message", "k", "", slog.Attr{}) }, "INFO  message k=\"\"\n"},
+	}
+
+	for _, tt := range tbl {
+		t.Run(tt.name, func(t *testing.T) {
+			buf := bytes.Buffer{}
+			l := New(Out(&buf), Err(&buf), Debug, Format("{{.Level}} {{.Message}}"))
+			tt.fn(slog.New(SlogHandler(l)))
+			assert.Equal(t, tt.res, buf.String())
+		})
+	}
+}
+
+func TestSlogHandlerEnabled(t *testing.T) {
+	h := SlogHandler(New())
+	assert.False(t, h.Enabled(context.Background(), slog.LevelDebug))
+	assert.True(t, h.Enabled(context.Background(), slog.LevelInfo))
+
+	h = SlogHandler(New(Trace))
+	assert.True(t, h.Enabled(context.Background(), slog.LevelDebug-4))
+
+	assert.False(t, SlogHandler(NoOpLogger).Enabled(context.Background(), slog.LevelError))
+}
+
+func TestSlogHandlerContextLevel(t *testing.T) {
+	buf := bytes.Buffer{}
+	l := slog.New(SlogHandler(New(Out(&buf), Format("{{.Level}} {{.Message}}"))))
+
+	l.DebugContext(context.Background(), "not enabled")
+	l.DebugContext(ContextWithLevel(context.Background(), "DEBUG"), "enabled for request")
+	l.Log(ContextWithLevel(context.Background(), "DEBUG"), slog.LevelDebug-4, "trace not enabled")
+	l.Log(ContextWithLevel(context.Background(), "TRACE"), slog.LevelDebug-4, "trace enabled for request")
+	l.InfoContext(ContextWithLevel(context.Background(), "WARN"), "info muted for request")
+	l.WarnContext(ContextWithLevel(context.Background(), "WARN"), "warn")
+	assert.Equal(t, "DEBUG enabled for request\nTRACE trace enabled for request\nWARN  warn\n", buf.String())
+}
+
+func TestSlogHandlerCaller(t *testing.T) {
+	buf := bytes.Buffer{}
+	l := New(Out(&buf), CallerFunc, CallerFile, Format("{{.CallerFile}} {{.CallerFunc}} {{.Message}}"))
+	slog.New(SlogHandler(l)).Info("message")
+	assert.Equal(t, "lgr/slog_test.go lgr.TestSlogHandlerCaller message\n", buf.String())
+}
+
+func TestSetAsSlogDefault(t *testing.T) {
+	defer restoreSlog()()
+
+	buf := bytes.Buffer{}
+	SetAsSlogDefault(New(Out(&buf), CallerFunc, Format("{{.Level}} {{.CallerFunc}} {{.Message}}")))
+	slog.Warn("message", "k", "v")
+	assert.Equal(t, "WARN  lgr.TestSetAsSlogDefault message k=v\n", buf.String())
+}
+
+func TestUseSlogDefault(t *testing.T) {
+	defer restoreSlog()()
+	origDef := def
+	defer func() { def = origDef }()
+
+	buf := bytes.Buffer{}
+	opts := &slog.HandlerOptions{AddSource: true, Level: slog.LevelInfo,
+		ReplaceAttr: func(_ []string, a slog.Attr) slog.Attr {
+			if a.Key == slog.TimeKey {
+				return slog.Attr{}
+			}
+			return a
+		}}
+	slog.SetDefault(slog.New(slog.NewTextHandler(&buf, opts)))
+
+	UseSlogDefault()
+	Printf("WARN message %d", 123)
+	Printf("DEBUG filtered by slog handler")
+	assert.Contains(t, buf.String(), "level=WARN source=")
+	assert.Contains(t, buf.String(), "lgr/slog_test.go:")
+	assert.Contains(t, buf.String(), ` msg="message 123"`)
+	assert.NotContains(t, buf.String(), "filtered")
+
+	// lgr handler installed as slog default doesn't loop back to slog
+	buf.Reset()
+	SetAsSlogDefault(New(Out(&buf), Format("{{.Level}} {{.Message}}")))
+	Printf("INFO message")
+	assert.Equal(t, "INFO  message\n", buf.String())
+}
+
+// restoreSlog saves slog default and standard log settings altered by slog.SetDefault, returns restore func
+func restoreSlog() func() {
+	origSlog, origOut, origFlags := slog.Default(), log.Writer(), log.Flags()
+	return func() {
+		slog.SetDefault(origSlog)
+		log.SetOutput(origOut)
+		log.SetFlags(origFlags)
+	}
+}
@@ -0,0 +1,74 @@
+package lgr
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// statusLine keeps state of transient status line
+type statusLine struct {
+	tty    bool // stdout is a terminal
+	active bool // status line is shown and has to be cleared before the next record
+}
+
+// clear erases status line, called under lock
+func (s *statusLine) clear(w io.Writer) {
+	_, _ = w.Write([]byte("\r\033[K"))
+	s.active = false
+}
+
+// Status shows transient status line, updated in place with each call, i.e. for progress reporting.
+// The line is cleared before the next record. If out writer is not a terminal, the status logged as a normal record,
+// with level prefix supported. Status lines are filtered by level, scrubbed and have secrets hidden as records,
+// but not mapped or formatted.
+func (l *Logger) Status(format string, args ...interface{}) {
+	if !l.status.tty {
+		l.logf(format, args...)
+		return
+	}
+
+	msg := format
+	if len(args) > 0 {
+		msg = fmt.Sprintf(format, args...)
+	}
+	lv, msg := ParseLevel(msg)
+	if !l.enabled(lv) {
+		return
+	}
+	if l.scrub != nil {
+		var keep bool
+		if msg, keep = l.scrub(msg); !keep {
+			return
+		}
+	}
+	msg = strings.ReplaceAll(strings.TrimSuffix(msg, "\n"), "\n", " ")
+
+	l.lock.Lock()
+	_, _ = l.stdout.Write(l.hideSecrets([]byte("\r\033[K" + msg)))
+	l.status.active = true
+	l.lock.Unlock()
+}
+
+// StatusDone clears transient status line, if shown
+func (l *Logger) StatusDone() {
+	l.lock.Lock()
+	if l.status.active {
+		l.status.clear(l.stdout)
+	}
+	l.lock.Unlock()
+}
+
+// isTerminal checks if writer is a terminal (character device)
+func isTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	st, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return st.Mode()&os.ModeCharDevice != 0
+}
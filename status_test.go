@@ -0,0 +1,76 @@
+package lgr
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoggerStatus(t *testing.T) {
+	rout, rerr := bytes.NewBuffer([]byte{}), bytes.NewBuffer([]byte{})
+	l := New(Out(rout), Err(rerr), Format(`{{.Level}} {{.Message}}`))
+	l.status.tty = true
+
+	l.Status("INFO processed %d%%", 10)
+	l.Status("processed %d%%", 20)
+	assert.Equal(t, "\r\033[Kprocessed 10%\r\033[Kprocessed 20%", rout.String())
+
+	rout.Reset()
+	l.Logf("WARN something")
+	l.Status("processed %d%%", 30)
+	l.StatusDone()
+	l.StatusDone()
+	l.Logf("INFO completed")
+	assert.Equal(t, "\r\033[KWARN  something\n\r\033[Kprocessed 30%\r\033[KINFO  completed\n", rout.String())
+
+	rout.Reset()
+	rdb := bytes.NewBuffer([]byte{})
+	l = New(Out(rout), Err(rerr), TagOut("db", rdb), Format(`{{.Level}} {{.Message}}`))
+	l.status.tty = true
+	l.Status("processed %d%%", 40)
+	l.Logf("INFO #db query")
+	assert.Equal(t, "INFO  #db query\n", rdb.String(), "routed record not cleared")
+	assert.Equal(t, "\r\033[Kprocessed 40%", rout.String())
+}
+
+func TestLoggerStatusNoTTY(t *testing.T) {
+	rout, rerr := bytes.NewBuffer([]byte{}), bytes.NewBuffer([]byte{})
+	l := New(Out(rout), Err(rerr), CallerFunc)
+	l.now = func() time.Time { return time.Date(2018, 1, 7, 13, 2, 34, 0, time.Local) }
+	l.Status("processed %d%%", 10)
+	l.StatusDone()
+	assert.Equal(t, "2018/01/07 13:02:34 INFO  {lgr.TestLoggerStatusNoTTY} processed 10%\n", rout.String())
+}
+
+func TestIsTerminal(t *testing.T) {
+	assert.False(t, isTerminal(bytes.NewBuffer([]byte{})))
+	f, err := os.CreateTemp(t.TempDir(), "test")
+	require.NoError(t, err)
+	defer f.Close()
+	assert.False(t, isTerminal(f))
+}
+
+func TestLoggerStatusFiltered(t *testing.T) {
+	rout := bytes.NewBuffer([]byte{})
+	l := New(Out(rout), Secret("hunter2"), Scrub(func(msg string) (string, bool) {
+		return strings.ReplaceAll(msg, "joe", "<user>"), !strings.Contains(msg, "forget")
+	}))
+	l.status.tty = true
+
+	l.Status("login joe with hunter2")
+	assert.Equal(t, "\r\033[Klogin <user> with ******", rout.String())
+
+	rout.Reset()
+	l.Status("DEBUG debug off")
+	l.Status("INFO forget me")
+	assert.Equal(t, "", rout.String(), "filtered by level and dropped by scrubber")
+
+	require.NoError(t, l.SetLevel("DEBUG"))
+	l.Status("DEBUG debug on")
+	assert.Equal(t, "\r\033[Kdebug on", rout.String())
+}
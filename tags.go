@@ -0,0 +1,148 @@
+package lgr
+
+import (
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"sync/atomic"
+)
+
+// tagRoute defines the writer for tagged messages
+type tagRoute struct {
+	out        io.Writer
+	sameStream bool // out and stderr are the same stream
+}
+
+// tagSample defines sampling rate for a tag, 1 of rate messages passed
+type tagSample struct {
+	rate    uint64
+	count   uint64 // atomic, shared with child loggers
+	dropped uint64 // atomic, number of messages dropped by sampling
+}
+
+// Tagged makes child logger adding tags to each message. Tags are functional categories, orthogonal to levels,
+// and can be muted or routed with MuteTags and TagOut options. Child shares writers and options with the parent.
+// Tags can be also set inline, with #tag words following the level, i.e. Logf("DEBUG #db #slow query %s", q).
+func (l *Logger) Tagged(tags ...string) *Logger {
+	res := l.clone()
+	res.tags = make([]string, 0, len(l.tags)+len(tags))
+	res.tags = append(res.tags, l.tags...)
+	for _, t := range tags {
+		if t = strings.TrimPrefix(strings.TrimSpace(t), "#"); t != "" {
+			res.tags = append(res.tags, t)
+		}
+	}
+	return res
+}
+
+// extractTags parses inline #tags at the beginning of the message and returns the message with stripped tags
+// and all tags of the message, including logger's own tags
+func (l *Logger) extractTags(msg string) (string, []string) {
+	if !strings.HasPrefix(msg, "#") {
+		return msg, l.tags
+	}
+	return parseTags(msg, append([]string{}, l.tags...))
+}
+
+// parseTags strips leading #tags from the message and appends them to tags
+func parseTags(msg string, tags []string) (string, []string) {
+	for strings.HasPrefix(msg, "#") {
+		end := strings.IndexAny(msg, " \t\n")
+		if end < 0 {
+			end = len(msg)
+		}
+		if end == 1 { // single # is not a tag
+			break
+		}
+		tags = append(tags, msg[1:end])
+		msg = strings.TrimLeft(msg[end:], " \t")
+	}
+	return msg, tags
+}
+
+// isMuted checks if any of tags muted
+func (l *Logger) isMuted(tags []string) bool {
+	if len(l.mutedTags) == 0 {
+		return false
+	}
+	for _, t := range tags {
+		if l.mutedTags[t] {
+			return true
+		}
+	}
+	return false
+}
+
+// sampled checks if message with given tags passes sampling. For multiple sampled tags the one with the lowest rate used,
+// i.e. tag never sampled (rate 1) always passes the message.
+func (l *Logger) sampled(tags []string) bool {
+	if len(l.tagSampling) == 0 {
+		return true
+	}
+	var sample *tagSample
+	for _, t := range tags {
+		if ts, ok := l.tagSampling[t]; ok && (sample == nil || ts.rate < sample.rate) {
+			sample = ts
+		}
+	}
+	if sample == nil || sample.rate <= 1 {
+		return true
+	}
+	if atomic.AddUint64(&sample.count, 1)%sample.rate != 1 {
+		atomic.AddUint64(&sample.dropped, 1)
+		return false
+	}
+	return true
+}
+
+// Dropped returns number of messages dropped by sampling for each sampled tag, shared with child loggers
+func (l *Logger) Dropped() map[string]uint64 {
+	res := make(map[string]uint64, len(l.tagSampling))
+	for t, ts := range l.tagSampling {
+		res[t] = atomic.LoadUint64(&ts.dropped)
+	}
+	return res
+}
+
+// ReportDropped logs WARN record with summary of messages dropped by sampling, i.e. "dropped by sampling: #db 99",
+// does nothing if nothing dropped. Designed to be called on shutdown, so suppression is never invisible.
+func (l *Logger) ReportDropped() {
+	dropped := l.Dropped()
+	tags := make([]string, 0, len(dropped))
+	for t, n := range dropped {
+		if n > 0 {
+			tags = append(tags, t)
+		}
+	}
+	if len(tags) == 0 {
+		return
+	}
+	sort.Strings(tags)
+	parts := make([]string, 0, len(tags))
+	for _, t := range tags {
+		parts = append(parts, "#"+t+" "+strconv.FormatUint(dropped[t], 10))
+	}
+	l.logf("WARN lgr: dropped by sampling: %s", strings.Join(parts, ", "))
+}
+
+// tagRoute returns route for the first routed tag
+func (l *Logger) tagRoute(tags []string) (tagRoute, bool) {
+	if len(l.tagRoutes) == 0 {
+		return tagRoute{}, false
+	}
+	for _, t := range tags {
+		if r, ok := l.tagRoutes[t]; ok {
+			return r, true
+		}
+	}
+	return tagRoute{}, false
+}
+
+// formatTags makes "#tag1 #tag2" string
+func formatTags(tags []string) string {
+	if len(tags) == 0 {
+		return ""
+	}
+	return "#" + strings.Join(tags, " #")
+}
@@ -0,0 +1,147 @@
+package lgr
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoggerTagged(t *testing.T) {
+	rout, rerr := bytes.NewBuffer([]byte{}), bytes.NewBuffer([]byte{})
+	l := New(Out(rout), Err(rerr), Debug, CallerFunc)
+	l.now = func() time.Time { return time.Date(2018, 1, 7, 13, 2, 34, 0, time.Local) }
+
+	l.Tagged("db", "#slow").Logf("DEBUG query %s", "select 1")
+	assert.Equal(t, "2018/01/07 13:02:34 DEBUG {lgr.TestLoggerTagged} #db #slow query select 1\n", rout.String())
+
+	rout.Reset()
+	l.Tagged("db").Tagged("", "slow").Logf("INFO query")
+	assert.Equal(t, "2018/01/07 13:02:34 INFO  {lgr.TestLoggerTagged} #db #slow query\n", rout.String())
+
+	rout.Reset()
+	l.Logf("INFO no tags")
+	assert.Equal(t, "2018/01/07 13:02:34 INFO  {lgr.TestLoggerTagged} no tags\n", rout.String(), "parent not affected")
+}
+
+func TestLoggerInlineTags(t *testing.T) {
+	tbl := []struct {
+		inp, out string
+	}{
+		{"INFO #db query", "INFO |#db|query\n"},
+		{"[WARN] #db   #slow  query", "WARN |#db #slow|query\n"},
+		{"INFO # not a tag", "INFO ||# not a tag\n"},
+		{"INFO #db", "INFO |#db|\n"},
+		{"INFO issue #123", "INFO ||issue #123\n"},
+	}
+
+	rout, rerr := bytes.NewBuffer([]byte{}), bytes.NewBuffer([]byte{})
+	l := New(Out(rout), Err(rerr), Format(`{{.Level}}|{{.Tags}}|{{.Message}}`))
+	for _, tt := range tbl {
+		rout.Reset()
+		l.Logf(tt.inp)
+		assert.Equal(t, tt.out, rout.String())
+	}
+
+	rout.Reset()
+	l.Tagged("svc").Logf("INFO #db query")
+	assert.Equal(t, "INFO |#svc #db|query\n", rout.String())
+}
+
+func TestLoggerMuteTags(t *testing.T) {
+	rout, rerr := bytes.NewBuffer([]byte{}), bytes.NewBuffer([]byte{})
+	l := New(Out(rout), Err(rerr), MuteTags("#db", "http"))
+	l.now = func() time.Time { return time.Date(2018, 1, 7, 13, 2, 34, 0, time.Local) }
+
+	l.Logf("INFO #db query")
+	l.Tagged("http").Logf("ERROR request failed")
+	l.Tagged("auth", "db").Logf("INFO login")
+	assert.Equal(t, "", rout.String())
+	assert.Equal(t, "", rerr.String())
+
+	l.Tagged("auth").Logf("INFO login")
+	assert.Equal(t, "2018/01/07 13:02:34 INFO  #auth login\n", rout.String())
+}
+
+func TestLoggerTagOut(t *testing.T) {
+	rout, rerr, rdb := bytes.NewBuffer([]byte{}), bytes.NewBuffer([]byte{}), bytes.NewBuffer([]byte{})
+	l := New(Out(rout), Err(rerr), TagOut("db", rdb))
+	l.now = func() time.Time { return time.Date(2018, 1, 7, 13, 2, 34, 0, time.Local) }
+
+	l.Logf("INFO #db query")
+	l.Tagged("db").Logf("ERROR query failed")
+	l.Logf("INFO not routed")
+	assert.Equal(t, "2018/01/07 13:02:34 INFO  #db query\n2018/01/07 13:02:34 ERROR #db query failed\n", rdb.String())
+	assert.Equal(t, "2018/01/07 13:02:34 INFO  not routed\n", rout.String())
+	assert.Equal(t, "2018/01/07 13:02:34 ERROR #db query failed\n", rerr.String())
+
+	rdb.Reset()
+	rerr.Reset()
+	l = New(Out(rout), Err(rdb), TagOut("db", rdb))
+	l.Logf("ERROR #db query failed")
+	assert.Contains(t, rdb.String(), " ERROR #db query failed\n")
+	assert.Equal(t, 1, bytes.Count(rdb.Bytes(), []byte("query failed")), "no duplicate to the same stream")
+}
+
+func TestLoggerSampleTags(t *testing.T) {
+	rout, rerr := bytes.NewBuffer([]byte{}), bytes.NewBuffer([]byte{})
+	l := New(Out(rout), Err(rerr), Format(`{{.Tags}} {{.Message}}`), SampleTags(map[string]int{"db": 3, "auth": 1, "http": 0}))
+
+	for i := 0; i < 7; i++ {
+		l.Logf("INFO #db %d", i)
+	}
+	assert.Equal(t, "#db 0\n#db 3\n#db 6\n", rout.String())
+
+	rout.Reset()
+	for i := 0; i < 3; i++ {
+		l.Tagged("db", "auth").Logf("INFO %d", i)
+		l.Logf("INFO #http %d", i)
+		l.Logf("INFO untagged %d", i)
+	}
+	assert.Equal(t, 9, bytes.Count(rout.Bytes(), []byte("\n")), "never sampled")
+
+	rout.Reset()
+	child := l.Tagged("db")
+	for i := 0; i < 3; i++ {
+		child.Logf("INFO %d", i)
+	}
+	assert.Equal(t, "#db 2\n", rout.String(), "counter shared with child")
+}
+
+func TestLoggerReportDropped(t *testing.T) {
+	rout, rerr := bytes.NewBuffer([]byte{}), bytes.NewBuffer([]byte{})
+	l := New(Out(rout), Err(rerr), Format(`{{.Level}} {{.Message}}`), SampleTags(map[string]int{"db": 3, "auth": 2, "http": 5}))
+
+	l.ReportDropped()
+	assert.Equal(t, "", rout.String(), "nothing dropped")
+
+	for i := 0; i < 7; i++ {
+		l.Logf("INFO #db %d", i)
+		l.Tagged("auth").Logf("INFO %d", i)
+	}
+	assert.Equal(t, map[string]uint64{"db": 4, "auth": 3, "http": 0}, l.Dropped())
+
+	rout.Reset()
+	l.ReportDropped()
+	assert.Equal(t, "WARN  lgr: dropped by sampling: #auth 3, #db 4\n", rout.String())
+}
+
+func TestLoggerTagsWithTemplate(t *testing.T) {
+	buf := bytes.Buffer{}
+	l := New(Out(&buf), Format(Short))
+	l.now = func() time.Time { return time.Date(2018, 1, 7, 13, 2, 34, 0, time.Local) }
+	l.Logf("INFO #42 order failed")
+	l.Tagged("db").Logf("WARN query failed")
+	assert.Equal(t, "2018/01/07 13:02:34 INFO  #42 order failed\n2018/01/07 13:02:34 WARN  #db query failed\n", buf.String())
+
+	buf.Reset()
+	l = New(Out(&buf), Format(`{{.Tags}}|{{.Message}}`))
+	l.Tagged("db").Logf("INFO #slow query")
+	assert.Equal(t, "#db #slow|query\n", buf.String(), "template places tags by itself")
+
+	buf.Reset()
+	l = New(Out(&buf), Format(GCP))
+	l.Logf("INFO #42 order failed")
+	assert.Contains(t, buf.String(), `"message":"#42 order failed"`)
+}
@@ -0,0 +1,10 @@
+//go:build !linux && !darwin
+
+package lgr
+
+import "os"
+
+// terminalWidth is not supported on this platform, returns 0
+func terminalWidth(*os.File) int {
+	return 0
+}
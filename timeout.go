@@ -0,0 +1,69 @@
+package lgr
+
+import (
+	"errors"
+	"io"
+	"sync/atomic"
+	"time"
+)
+
+// ErrWriteTimeout returned by writers wrapped with WriteTimeout if write not completed in time
+var ErrWriteTimeout = errors.New("lgr: write timeout")
+
+// timeoutWriter limits duration of each write. Uses SetWriteDeadline if supported by the writer, i.e. for pipes
+// and sockets, otherwise writes in goroutine and stops waiting on timeout. Writes fail fast while the timed out
+// write is still blocked, so a hung writer never freezes goroutines logging to it.
+type timeoutWriter struct {
+	w       io.Writer
+	timeout time.Duration
+	stuck   int32 // atomic, set while timed out write is still in progress
+}
+
+type deadlineWriter interface {
+	SetWriteDeadline(t time.Time) error
+}
+
+// Write to the underlying writer with timeout
+func (t *timeoutWriter) Write(p []byte) (int, error) {
+	if atomic.LoadInt32(&t.stuck) == 1 {
+		return 0, ErrWriteTimeout
+	}
+
+	if dw, ok := t.w.(deadlineWriter); ok {
+		if err := dw.SetWriteDeadline(time.Now().Add(t.timeout)); err == nil {
+			defer dw.SetWriteDeadline(time.Time{}) //nolint:errcheck // reset deadline for other users of the writer
+			return t.w.Write(p)
+		}
+	}
+
+	type result struct {
+		n   int
+		err error
+	}
+	done := make(chan result, 1)
+	data := append([]byte(nil), p...) // caller may reuse p after timeout
+	go func() {
+		n, err := t.w.Write(data)
+		done <- result{n: n, err: err}
+		atomic.StoreInt32(&t.stuck, 0) // after send, so can't be overwritten by the timed out Write
+	}()
+
+	timer := time.NewTimer(t.timeout)
+	defer timer.Stop()
+	select {
+	case r := <-done:
+		return r.n, r.err
+	case <-timer.C:
+		atomic.StoreInt32(&t.stuck, 1)
+		select {
+		case r := <-done: // completed right on timeout
+			atomic.StoreInt32(&t.stuck, 0)
+			return r.n, r.err
+		default:
+			return 0, ErrWriteTimeout
+		}
+	}
+}
+
+// Unwrap returns the underlying writer
+func (t *timeoutWriter) Unwrap() io.Writer { return t.w }
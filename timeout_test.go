@@ -0,0 +1,78 @@
+package lgr
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTimeoutWriter(t *testing.T) {
+	bw := &blockingWriter{unblock: make(chan struct{})}
+	tw := &timeoutWriter{w: bw, timeout: 20 * time.Millisecond}
+
+	st := time.Now()
+	n, err := tw.Write([]byte("blocked"))
+	assert.Equal(t, ErrWriteTimeout, err)
+	assert.Equal(t, 0, n)
+	assert.Less(t, time.Since(st), time.Second)
+
+	_, err = tw.Write([]byte("dropped"))
+	assert.Equal(t, ErrWriteTimeout, err, "fail fast while stuck")
+
+	close(bw.unblock)
+	require.Eventually(t, func() bool {
+		_, err = tw.Write([]byte(" passed"))
+		return err == nil
+	}, time.Second, 5*time.Millisecond)
+	assert.Equal(t, "blocked passed", bw.String())
+}
+
+func TestTimeoutWriterDeadline(t *testing.T) {
+	r, w, err := os.Pipe()
+	require.NoError(t, err)
+	defer r.Close()
+	defer w.Close()
+
+	tw := &timeoutWriter{w: w, timeout: 20 * time.Millisecond}
+	_, err = tw.Write(make([]byte, 1024*1024)) // larger than pipe buffer, nobody reads
+	assert.True(t, errors.Is(err, os.ErrDeadlineExceeded), "%v", err)
+	assert.Equal(t, int32(0), atomic.LoadInt32(&tw.stuck), "deadline doesn't leave writer stuck")
+}
+
+func TestLoggerWriteTimeout(t *testing.T) {
+	bw := &blockingWriter{unblock: make(chan struct{})}
+	defer close(bw.unblock)
+	l := New(Out(bw), Err(bw), WriteTimeout(10*time.Millisecond))
+
+	st := time.Now()
+	l.Logf("INFO blocked")
+	l.Logf("ERROR blocked")
+	assert.Less(t, time.Since(st), time.Second)
+	assert.True(t, l.sameStream, "same stream detected for wrapped writers")
+}
+
+type blockingWriter struct {
+	unblock chan struct{}
+	mu      sync.Mutex
+	buf     bytes.Buffer
+}
+
+func (b *blockingWriter) Write(p []byte) (int, error) {
+	<-b.unblock
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *blockingWriter) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
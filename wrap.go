@@ -0,0 +1,103 @@
+package lgr
+
+import (
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	defaultWrapWidth = 80 // used if terminal width is unknown
+	minWrapWidth     = 20 // narrower hanging indentation dropped
+)
+
+// wrapRecord soft-wraps the message within the rendered record, continuation lines indented under the message start.
+// The record returned as is if the message can't be located, i.e. altered by mapper.
+func (l *Logger) wrapRecord(data []byte, msg string) []byte {
+	msg = string(l.hideSecrets([]byte(msg)))
+	rec := string(data)
+	idx := strings.Index(rec, msg)
+	if msg == "" || idx < 0 {
+		return data
+	}
+	header := rec[:idx]
+	if nl := strings.LastIndexByte(header, '\n'); nl >= 0 {
+		header = header[nl+1:]
+	}
+	return []byte(rec[:idx] + wrapText(msg, visibleWidth(header), l.wrapWidth) + rec[idx+len(msg):])
+}
+
+// wrapText breaks text on spaces so each line fits width, the first line starting at indent column.
+// Continuation lines, including lines of multiline text, indented with spaces, words longer than a line broken.
+func wrapText(s string, indent, width int) string {
+	pad := indent
+	if width-pad < minWrapWidth {
+		pad = 0
+	}
+	avail := width - indent
+	if avail < minWrapWidth {
+		avail = minWrapWidth
+	}
+
+	lines := []string{}
+	for _, para := range strings.Split(s, "\n") {
+		if visibleWidth(para) <= avail {
+			lines = append(lines, para)
+			avail = width - pad
+			continue
+		}
+		line := ""
+		for _, word := range strings.Split(para, " ") {
+			if line != "" && visibleWidth(line)+1+visibleWidth(word) > avail {
+				lines = append(lines, line)
+				line, avail = "", width-pad
+			}
+			if line != "" {
+				line += " "
+			}
+			line += word
+			for visibleWidth(line) > avail { // word doesn't fit even on its own line
+				cut := runeOffset(line, avail)
+				lines = append(lines, line[:cut])
+				line, avail = line[cut:], width-pad
+			}
+		}
+		lines = append(lines, line)
+		avail = width - pad
+	}
+	return strings.Join(lines, "\n"+strings.Repeat(" ", pad))
+}
+
+// visibleWidth returns number of runes without ANSI escape sequences
+func visibleWidth(s string) int {
+	if strings.IndexByte(s, 0x1b) >= 0 {
+		s = reANSI.ReplaceAllString(s, "")
+	}
+	return utf8.RuneCountInString(s)
+}
+
+// runeOffset returns byte offset of n-th rune
+func runeOffset(s string, n int) int {
+	for i := range s {
+		if n == 0 {
+			return i
+		}
+		n--
+	}
+	return len(s)
+}
+
+// consoleWidth returns width of the terminal w, $COLUMNS or default width
+func consoleWidth(w io.Writer) int {
+	if f, ok := w.(*os.File); ok {
+		if n := terminalWidth(f); n > 0 {
+			return n
+		}
+	}
+	if n, err := strconv.Atoi(os.Getenv("COLUMNS")); err == nil && n > 0 {
+		return n
+	}
+	return defaultWrapWidth
+}
@@ -0,0 +1,52 @@
+package lgr
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWrapText(t *testing.T) {
+	tbl := []struct {
+		name   string
+		inp    string
+		indent int
+		width  int
+		res    string
+	}{
+		{"fits", "short message", 6, 40, "short message"},
+		{"wrapped", "failed to connect: dial tcp: lookup db: no such host", 6, 30,
+			"failed to connect: dial\n      tcp: lookup db: no such\n      host"},
+		{"long word", "key=0123456789012345678901234567890123456789", 0, 20,
+			"key=0123456789012345\n67890123456789012345\n6789"},
+		{"multiline", "first line\nsecond line is longer than width", 4, 26,
+			"first line\n    second line is longer\n    than width"},
+		{"narrow", "message wider than the rest of the line", 20, 30,
+			"message wider than\nthe rest of the line"},
+		{"unicode", "привет мир, привет мир", 2, 22, "привет мир, привет\n  мир"},
+		{"escapes not counted", "\x1b[31mcolored\x1b[0m text here", 0, 20, "\x1b[31mcolored\x1b[0m text here"},
+	}
+
+	for _, tt := range tbl {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.res, wrapText(tt.inp, tt.indent, tt.width))
+		})
+	}
+}
+
+func TestLoggerSoftWrap(t *testing.T) {
+	rout, rerr := bytes.Buffer{}, bytes.Buffer{}
+	l := New(Out(&rout), Err(&rerr), Format(`{{.Level}} {{.Message}}`), SoftWrap(30), Secret("password"))
+	assert.False(t, l.wrapOut, "not a terminal")
+	l.Logf("INFO long message written to file as is")
+	assert.Equal(t, "INFO  long message written to file as is\n", rout.String())
+
+	rout.Reset()
+	l.wrapOut = true // pretend stdout is a terminal
+	l.Logf("ERROR long message with password wrapped for terminal")
+	assert.Equal(t, "ERROR long message with ******\n      wrapped for terminal\n", rout.String())
+	assert.Equal(t, "ERROR long message with ****** wrapped for terminal\n", rerr.String())
+
+	assert.Greater(t, New(Out(&rout), Err(&rout), SoftWrap(0)).wrapWidth, 0, "width resolved by New")
+}